@@ -1,109 +1,40 @@
 package tfhe
 
 import (
-	"github.com/sp301415/tfhe-go/math/num"
-	"github.com/sp301415/tfhe-go/math/vec"
+	"sync"
 )
 
 func (e *Evaluator[T]) GenCompressLUTAssign(lutOut LookUpTable[T]) {
-	baseMessageModulus := e.Parameters.messageModulus
-	polyDegree := 2048
-	lutRaw := make([]T, polyDegree)
-	for x := T(0); x < baseMessageModulus/2; x++ {
-		start := num.DivRound(2*int(x)*polyDegree, int(baseMessageModulus))
-		end := num.DivRound(2*(int(x)+1)*polyDegree, int(baseMessageModulus))
-		for xx := start; xx < end; xx++ {
-			lutRaw[xx] = (T(1)<<63/baseMessageModulus)*x + (T(1) << 62 / baseMessageModulus)
-		}
-	}
-
-	offset := num.DivRound(polyDegree, int(baseMessageModulus))
-
-	vec.RotateInPlace(lutRaw, -offset)
-	for i := polyDegree - offset; i < polyDegree; i++ {
-		lutRaw[i] = -lutRaw[i]
-	}
-	for j := 0; j < polyDegree; j++ {
-		lutOut.Value[0].Coeffs[j] = lutRaw[j]
-	}
+	// After modulus switching, inputs are BaseExtendFactor entries apart,
+	// so the LUT is generated over BaseMessageModulus to maximize the margin.
+	baseMessageModulus := e.Parameters.BaseMessageModulus()
+	halfQ := T(1) << (e.Parameters.logQ - 1)
+	f := func(x int) T { return (halfQ/baseMessageModulus)*T(x) + (halfQ / 2 / baseMessageModulus) }
+	e.lookUpTableBuilder(1, e.Parameters.basePolyDegree, NegacyclicPadding, OffsetCellStart).BuildAssign(f, baseMessageModulus/2, lutOut)
 }
 
 func (e *Evaluator[T]) BootstrapFullDomainAssign(ct LWECiphertext[T], lutCompress LookUpTable[T], lutEval LookUpTable[T], ctOut LWECiphertext[T]) {
 	e.BootstrapLUTAssign(e.BootstrapLUT(ct, lutCompress), lutEval, ctOut)
 }
 
-func (e *Evaluator[T]) BootstrapExtendedFullDomainAssignNew(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLut []LookUpTable[T], ctOut LWECiphertext[T]) {
+func (e *Evaluator[T]) BootstrapExtendedFullDomainAssignNew(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT DecomposedLookUpTable[T], ctOut LWECiphertext[T]) {
 	e.KeySwitchForBootstrapAssign(ct, e.buffer.ctKeySwitchForBootstrap)
-	e.BlindRotateExtendedFullDomainAssignNew(e.buffer.ctKeySwitchForBootstrap, lutCompress, decomposedLut, e.buffer.ctRotate)
+	e.BlindRotateExtendedFullDomainAssignNew(e.buffer.ctKeySwitchForBootstrap, lutCompress, decomposedLUT, e.buffer.ctRotate)
 	e.buffer.ctRotate.ToLWECiphertextAssign(0, ctOut)
 }
-func (e *Evaluator[T]) BlindRotateExtendedFullDomainAssignNew(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT []LookUpTable[T], ctOut GLWECiphertext[T]) {
-
-	e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT[0], e.Parameters.polyExtendFactor/2, ctOut)
-	// Evaluate NegLUT
-	for i := 1; i < len(decomposedLUT)-1; i++ {
-		e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT[i], e.Parameters.polyExtendFactor/(1<<(i+1)), e.buffer.ctEBSAcc)
-		e.AddGLWEAssign(e.buffer.ctEBSAcc, ctOut, ctOut)
-	}
-
-	// FDFB
-	// First BTS
-	e.blindRotateLUTCompressAssign(ct, lutCompress, e.buffer.ctEBSAcc)
-	e.buffer.ctEBSAcc.ToLWECiphertextAssign(0, e.buffer.ctLWEExtracted)
-	e.KeySwitchForBootstrapAssign(e.buffer.ctLWEExtracted, e.buffer.ctKeySwitchForBootstrap)
-
-	// second BTS
-	e.blindRotateBaseLUTAssign(e.buffer.ctKeySwitchForBootstrap, decomposedLUT[len(decomposedLUT)-1], e.buffer.ctEBSAcc)
-	e.AddGLWEAssign(e.buffer.ctEBSAcc, ctOut, ctOut)
+func (e *Evaluator[T]) BlindRotateExtendedFullDomainAssignNew(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT DecomposedLookUpTable[T], ctOut GLWECiphertext[T]) {
+	e.BlindRotateExtendedFullDomainAssign(ct, lutCompress, decomposedLUT, ctOut)
 }
 
 func (e *Evaluator[T]) GenExtendedCompressLUTAssign(lutOut LookUpTable[T]) {
-
-	for x := T(0); x < e.Parameters.messageModulus/2; x++ {
-		start := num.DivRound(2*int(x)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		end := num.DivRound(2*(int(x)+1)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		for xx := start; xx < end; xx++ {
-			e.buffer.lutRaw[xx] = (T(1)<<63/e.Parameters.messageModulus)*x + (T(1) << 62 / e.Parameters.messageModulus)
-		}
-	}
-
-	offset := num.DivRound(e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-	vec.RotateInPlace(e.buffer.lutRaw, -offset)
-	for i := e.Parameters.lookUpTableSize - offset; i < e.Parameters.lookUpTableSize; i++ {
-		e.buffer.lutRaw[i] = -e.buffer.lutRaw[i]
-	}
-
-	for i := 0; i < e.Parameters.polyExtendFactor; i++ {
-		for j := 0; j < e.Parameters.polyDegree; j++ {
-			lutOut.Value[i].Coeffs[j] = e.buffer.lutRaw[j*e.Parameters.polyExtendFactor+i]
-		}
-	}
+	messageModulus := e.Parameters.messageModulus
+	halfQ := T(1) << (e.Parameters.logQ - 1)
+	f := func(x int) T { return (halfQ/messageModulus)*T(x) + (halfQ / 2 / messageModulus) }
+	e.lookUpTableBuilder(e.Parameters.polyExtendFactor, e.Parameters.polyDegree, NegacyclicPadding, OffsetCellStart).BuildAssign(f, messageModulus/2, lutOut)
 }
 
 func (e *Evaluator[T]) GenExtendedFDFBLookUpTableCustomFullAssign(f func(int) T, messageModulus T, lutOut LookUpTable[T]) {
-	for x := T(0); x < e.Parameters.messageModulus/2; x++ {
-		start := num.DivRound(int(x)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		end := num.DivRound((int(x)+1)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		y := f(int(x))
-		for xx := start; xx < end; xx++ {
-			e.buffer.lutRaw[xx] = y
-		}
-	}
-
-	for x := e.Parameters.messageModulus / 2; x < e.Parameters.messageModulus; x++ {
-		start := num.DivRound(int(x)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		end := num.DivRound((int(x)+1)*e.Parameters.lookUpTableSize, int(e.Parameters.messageModulus))
-		y := f(int(e.Parameters.messageModulus - x + e.Parameters.messageModulus/2 - 1))
-		for xx := start; xx < end; xx++ {
-			e.buffer.lutRaw[xx] = -y
-		}
-	}
-
-	for i := 0; i < e.Parameters.polyExtendFactor; i++ {
-		for j := 0; j < e.Parameters.polyDegree; j++ {
-			lutOut.Value[i].Coeffs[j] = e.buffer.lutRaw[j*e.Parameters.polyExtendFactor+i]
-		}
-	}
+	e.lookUpTableBuilder(e.Parameters.polyExtendFactor, e.Parameters.polyDegree, NegacyclicMirror, OffsetCellCenter).BuildAssign(f, e.Parameters.messageModulus, lutOut)
 }
 
 func (e *Evaluator[T]) GenExtendedFDFBLookUpTableAssign(f func(int) int, lutOut LookUpTable[T]) {
@@ -137,12 +68,19 @@ func (e *Evaluator[T]) FDFBLUTAssign(ct LWECiphertext[T], compressLUT LookUpTabl
 	}
 }
 
-func (e *Evaluator[T]) BlindRotateExtendedFullDomainAssign(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT []LookUpTable[T], ctOut GLWECiphertext[T]) {
+func (e *Evaluator[T]) BlindRotateExtendedFullDomainAssign(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT DecomposedLookUpTable[T], ctOut GLWECiphertext[T]) {
+
+	checkDecomposedLUT(decomposedLUT, e.Parameters, true)
+
+	if e.fullDomainBranches != nil {
+		e.blindRotateExtendedFullDomainConcurrentAssign(ct, lutCompress, decomposedLUT, ctOut)
+		return
+	}
 
-	e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT[0], e.Parameters.polyExtendFactor/2, ctOut)
+	e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT.NegLUTs[0], e.Parameters.polyExtendFactor/2, ctOut)
 	// Evaluate NegLUT
-	for i := 1; i < len(decomposedLUT)-1; i++ {
-		e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT[i], e.Parameters.polyExtendFactor/(1<<(i+1)), e.buffer.ctEBSAcc)
+	for i := 1; i < len(decomposedLUT.NegLUTs); i++ {
+		e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT.NegLUTs[i], e.Parameters.polyExtendFactor/(1<<(i+1)), e.buffer.ctEBSAcc)
 		e.AddGLWEAssign(e.buffer.ctEBSAcc, ctOut, ctOut)
 	}
 	// FDFB
@@ -152,6 +90,36 @@ func (e *Evaluator[T]) BlindRotateExtendedFullDomainAssign(ct LWECiphertext[T],
 	e.KeySwitchForBootstrapAssign(e.buffer.ctLWEExtracted, e.buffer.ctKeySwitchForBootstrap)
 
 	// second BTS
-	e.blindRotateBaseLUTAssign(e.buffer.ctKeySwitchForBootstrap, decomposedLUT[len(decomposedLUT)-1], e.buffer.ctEBSAcc)
+	e.blindRotateBaseLUTAssign(e.buffer.ctKeySwitchForBootstrap, decomposedLUT.BaseLUT, e.buffer.ctEBSAcc)
 	e.AddGLWEAssign(e.buffer.ctEBSAcc, ctOut, ctOut)
 }
+
+// blindRotateExtendedFullDomainConcurrentAssign is the concurrent version of BlindRotateExtendedFullDomainAssign.
+// Each NegLUT and the compress bootstrapping is evaluated by its own branch evaluator.
+func (e *Evaluator[T]) blindRotateExtendedFullDomainConcurrentAssign(ct LWECiphertext[T], lutCompress LookUpTable[T], decomposedLUT DecomposedLookUpTable[T], ctOut GLWECiphertext[T]) {
+	negLUTCount := len(decomposedLUT.NegLUTs)
+
+	var wg sync.WaitGroup
+	wg.Add(negLUTCount + 1)
+	for i := 0; i < negLUTCount; i++ {
+		go func(i int) {
+			branch := e.fullDomainBranches[i]
+			branch.blindRotateArbitraryExtendedAssign(ct, decomposedLUT.NegLUTs[i], e.Parameters.polyExtendFactor/(1<<(i+1)), branch.buffer.ctEBSAcc)
+			wg.Done()
+		}(i)
+	}
+	go func() {
+		branch := e.fullDomainBranches[negLUTCount]
+		branch.blindRotateLUTCompressAssign(ct, lutCompress, branch.buffer.ctEBSAcc)
+		branch.buffer.ctEBSAcc.ToLWECiphertextAssign(0, branch.buffer.ctLWEExtracted)
+		branch.KeySwitchForBootstrapAssign(branch.buffer.ctLWEExtracted, branch.buffer.ctKeySwitchForBootstrap)
+		branch.blindRotateBaseLUTAssign(branch.buffer.ctKeySwitchForBootstrap, decomposedLUT.BaseLUT, branch.buffer.ctEBSAcc)
+		wg.Done()
+	}()
+	wg.Wait()
+
+	ctOut.CopyFrom(e.fullDomainBranches[0].buffer.ctEBSAcc)
+	for i := 1; i < negLUTCount+1; i++ {
+		e.AddGLWEAssign(e.fullDomainBranches[i].buffer.ctEBSAcc, ctOut, ctOut)
+	}
+}
@@ -0,0 +1,66 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOurFDFBEBSConcurrent(t *testing.T) {
+	params := tfhe.ParamsEBS6.Compile()
+	enc := tfhe.NewEncryptor(params)
+	eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+	evalConcurrent := eval.ShallowCopy()
+	evalConcurrent.SetConcurrentFullDomain(true)
+
+	f := func(x int) int { return 2*x + 5 }
+	decomposedlut := eval.NewDecomposedLutEBS()
+	eval.GenLookUpTableNegDecomposedEBSAssign(f, params.MessageModulus(), params.Scale(), &decomposedlut)
+	compressLUT := tfhe.NewLookUpTable(params)
+	eval.GenCompressLUTAssign(compressLUT)
+
+	messageModulus := int(params.MessageModulus())
+	for _, x := range []int{0, messageModulus/2 + 1, messageModulus - 1} {
+		ct := enc.EncryptLWE(x)
+		ctOut := ct.Copy()
+		ctOutConcurrent := ct.Copy()
+
+		eval.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedlut, ctOut)
+		evalConcurrent.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedlut, ctOutConcurrent)
+		assert.Equal(t, ctOut, ctOutConcurrent)
+		assert.Equal(t, f(x)%messageModulus, enc.DecryptLWE(ctOutConcurrent))
+	}
+}
+
+func Benchmark_OurFDFBEBSConcurrent(b *testing.B) {
+	for _, params := range paramsListNewEBS {
+		params := params.Compile()
+		enc := tfhe.NewEncryptor(params)
+		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+		evalConcurrent := eval.ShallowCopy()
+		evalConcurrent.SetConcurrentFullDomain(true)
+
+		decomposedlut := eval.NewDecomposedLutEBS()
+		eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 13 - 2*x }, eval.Parameters.MessageModulus(), eval.Parameters.Scale(), &decomposedlut)
+		compressLUT := tfhe.NewLookUpTable(eval.Parameters)
+		eval.GenCompressLUTAssign(compressLUT)
+
+		ct := enc.EncryptLWE(0)
+		ctOut := ct.Copy()
+
+		b.Run(fmt.Sprintf("prec=%v/sequential", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				eval.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedlut, ctOut)
+			}
+		})
+
+		b.Run(fmt.Sprintf("prec=%v/concurrent", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				evalConcurrent.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedlut, ctOut)
+			}
+		})
+	}
+}
@@ -40,7 +40,7 @@ func Benchmark_OurFDFBEBS(b *testing.B) {
 		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
 
 		decomposedlut := eval.NewDecomposedLutEBS()
-		eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 13 - 2*x }, eval.Parameters.MessageModulus(), eval.Parameters.Scale(), decomposedlut)
+		eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 13 - 2*x }, eval.Parameters.MessageModulus(), eval.Parameters.Scale(), &decomposedlut)
 		compressLUT := tfhe.NewLookUpTable(eval.Parameters)
 		eval.GenCompressLUTAssign(compressLUT)
 
@@ -62,7 +62,7 @@ func Example_ourFDFBEBS() {
 	eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
 
 	decomposedlut := eval.NewDecomposedLutEBS()
-	eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 18 - 3*x }, eval.Parameters.MessageModulus(), eval.Parameters.Scale(), decomposedlut)
+	eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 18 - 3*x }, eval.Parameters.MessageModulus(), eval.Parameters.Scale(), &decomposedlut)
 	compressLUT := tfhe.NewLookUpTable(eval.Parameters)
 	eval.GenCompressLUTAssign(compressLUT)
 
@@ -17,6 +17,18 @@ var (
 		tfhe.Params7,
 		tfhe.Params8,
 	}
+
+	paramsListUint32 = []tfhe.ParametersLiteral[uint32]{
+		tfhe.Params2Uint32,
+		tfhe.Params3Uint32,
+		tfhe.Params4Uint32,
+	}
+
+	paramsListEBSUint32 = []tfhe.ParametersLiteral[uint32]{
+		tfhe.ParamsEBS2Uint32,
+		tfhe.ParamsEBS3Uint32,
+		tfhe.ParamsEBS4Uint32,
+	}
 )
 
 func TestParamsNew(t *testing.T) {
@@ -33,6 +45,89 @@ func TestParamsNew(t *testing.T) {
 	}
 }
 
+func TestParamsUint32(t *testing.T) {
+	for _, params := range paramsListUint32 {
+		t.Run(fmt.Sprintf("FailureProbability/ParamsUint%v", num.Log2(params.MessageModulus)), func(t *testing.T) {
+			assert.LessOrEqual(t, math.Log2(params.Compile().EstimateFailureProbabilityNewFDFB()), -60.0)
+		})
+	}
+
+	for _, params := range paramsListEBSUint32 {
+		t.Run(fmt.Sprintf("FailureProbability/ParamsEBSUint%v", num.Log2(params.MessageModulus)), func(t *testing.T) {
+			assert.LessOrEqual(t, math.Log2(params.Compile().EstimateFailureProbabilityNewFDFB_EBS()), -60.0)
+		})
+	}
+
+	t.Run("CompressLUT", func(t *testing.T) {
+		params := tfhe.Params4Uint32.Compile()
+		params64 := tfhe.Params5.WithPolyDegree(params.PolyDegree()).WithLookUpTableSize(params.LookUpTableSize()).
+			WithBasePolyDegree(params.BasePolyDegree()).WithLWEDimension(params.LWEDimension()).WithMessageModulus(uint64(params.MessageModulus())).Compile()
+
+		lut := tfhe.NewLookUpTableCustom[uint32](1, params.BasePolyDegree())
+		tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint32]{}).GenCompressLUTAssign(lut)
+		lut64 := tfhe.NewLookUpTableCustom[uint64](1, params64.BasePolyDegree())
+		tfhe.NewEvaluator(params64, tfhe.EvaluationKey[uint64]{}).GenCompressLUTAssign(lut64)
+
+		for i := range lut.Value[0].Coeffs {
+			assert.Equal(t, uint32(lut64.Value[0].Coeffs[i]>>32), lut.Value[0].Coeffs[i])
+		}
+	})
+
+	for _, paramsLiteral := range paramsListUint32 {
+		params := paramsLiteral.Compile()
+		messageModulus := int(params.MessageModulus())
+		f := func(x int) int { return 3*x + 1 }
+
+		t.Run(fmt.Sprintf("BootstrapFunc/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+			eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+
+			for _, x := range []int{0, messageModulus/2 - 1, messageModulus / 2, messageModulus - 1} {
+				ct := eval.BootstrapFunc(enc[0].EncryptLWE(x), f)
+				assert.Equal(t, f(x)%messageModulus, enc[0].DecryptLWE(ct))
+			}
+		})
+
+		t.Run(fmt.Sprintf("FDFBLUTAssign/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			enc := tfhe.NewEncryptor(params)
+			eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+			compressLUT := tfhe.NewLookUpTable(params)
+			eval.GenExtendedCompressLUTAssign(compressLUT)
+			fdfbLUT := tfhe.NewLookUpTable(params)
+			eval.GenExtendedFDFBLookUpTableAssign(f, fdfbLUT)
+
+			for x := 0; x < messageModulus; x++ {
+				ct := enc.EncryptLWE(x)
+				eval.FDFBLUTAssign(ct, compressLUT, fdfbLUT, ct)
+				assert.Equal(t, f(x)%messageModulus, enc.DecryptLWE(ct), "f(%v)", x)
+			}
+		})
+	}
+
+	for _, paramsLiteral := range paramsListEBSUint32 {
+		params := paramsLiteral.Compile()
+		messageModulus := int(params.MessageModulus())
+		f := func(x int) int { return 3*x + 1 }
+
+		t.Run(fmt.Sprintf("EBS/ParamsEBSUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			enc := tfhe.NewEncryptor(params)
+			eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+			decomposedLUT := eval.NewDecomposedLutEBS()
+			eval.GenLookUpTableNegDecomposedEBSAssign(f, params.MessageModulus(), params.Scale(), &decomposedLUT)
+			compressLUT := tfhe.NewLookUpTable(params)
+			eval.GenCompressLUTAssign(compressLUT)
+
+			for x := 0; x < messageModulus; x++ {
+				ct := enc.EncryptLWE(x)
+				eval.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedLUT, ct)
+				assert.Equal(t, f(x)%messageModulus, enc.DecryptLWE(ct), "f(%v)", x)
+			}
+		})
+	}
+}
+
 func Benchmark_OurFDFB(b *testing.B) {
 	for _, params := range paramsListNew {
 		params := params.Compile()
@@ -49,7 +144,7 @@ func Benchmark_OurFDFB(b *testing.B) {
 		baseEval := tfhe.NewEvaluator(params, baseEnc.GenEvaluationKeyParallel())
 
 		decomposedlut := baseEval.NewDecomposedLut()
-		baseEval.GenLookUpTableNegDecomposedAssign(func(x int) int { return 13 + x }, baseEval.Parameters.MessageModulus(), baseEval.Parameters.Scale(), decomposedlut)
+		baseEval.GenLookUpTableNegDecomposedAssign(func(x int) int { return 13 + x }, baseEval.Parameters.MessageModulus(), baseEval.Parameters.Scale(), &decomposedlut)
 		compressLUT := tfhe.NewLookUpTable(evaluators[len(evaluators)-1].Parameters)
 
 		baseEval.GenCompressLUTAssign(compressLUT)
@@ -61,10 +156,10 @@ func Benchmark_OurFDFB(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				ctOut := enc[0].EncryptLWE(0)
 				for depth := 0; depth < len(evaluators); depth++ {
-					evaluators[depth].AddLWEAssign(ctOut, evaluators[depth].BootstrapLUTWithMSconst(ct, decomposedlut[depth], MSConst), ctOut)
+					evaluators[depth].AddLWEAssign(ctOut, evaluators[depth].BootstrapLUTWithMSconst(ct, decomposedlut.NegLUTs[depth], MSConst), ctOut)
 				}
 				evaluators[len(evaluators)-1].BootstrapLUTWithMSconstAssign(ct, compressLUT, MSConst*2, ctCompress)
-				evaluators[len(evaluators)-1].AddLWEAssign(ctOut, evaluators[len(evaluators)-1].BootstrapLUT(ctCompress, decomposedlut[len(decomposedlut)-1]), ctOut)
+				evaluators[len(evaluators)-1].AddLWEAssign(ctOut, evaluators[len(evaluators)-1].BootstrapLUT(ctCompress, decomposedlut.BaseLUT), ctOut)
 			}
 		})
 	}
@@ -83,7 +178,7 @@ func Example_ourFDFB() {
 	baseEval := tfhe.NewEvaluator(params, baseEnc.GenEvaluationKeyParallel())
 
 	decomposedlut := baseEval.NewDecomposedLut()
-	baseEval.GenLookUpTableNegDecomposedAssign(func(x int) int { return 18 - 3*x }, baseEval.Parameters.MessageModulus(), baseEval.Parameters.Scale(), decomposedlut)
+	baseEval.GenLookUpTableNegDecomposedAssign(func(x int) int { return 18 - 3*x }, baseEval.Parameters.MessageModulus(), baseEval.Parameters.Scale(), &decomposedlut)
 	compressLUT := tfhe.NewLookUpTable(evaluators[len(evaluators)-1].Parameters)
 
 	baseEval.GenCompressLUTAssign(compressLUT)
@@ -92,10 +187,10 @@ func Example_ourFDFB() {
 	ctCompress := ct.Copy()
 	MSConst := baseEval.ModSwitchConstant()
 	for depth := 0; depth < len(evaluators); depth++ {
-		evaluators[depth].AddLWEAssign(ctOut, evaluators[depth].BootstrapLUTWithMSconst(ct, decomposedlut[depth], MSConst), ctOut)
+		evaluators[depth].AddLWEAssign(ctOut, evaluators[depth].BootstrapLUTWithMSconst(ct, decomposedlut.NegLUTs[depth], MSConst), ctOut)
 	}
 	evaluators[len(evaluators)-1].BootstrapLUTWithMSconstAssign(ct, compressLUT, MSConst*2, ctCompress)
-	evaluators[len(evaluators)-1].AddLWEAssign(ctOut, evaluators[len(evaluators)-1].BootstrapLUT(ctCompress, decomposedlut[len(decomposedlut)-1]), ctOut)
+	evaluators[len(evaluators)-1].AddLWEAssign(ctOut, evaluators[len(evaluators)-1].BootstrapLUT(ctCompress, decomposedlut.BaseLUT), ctOut)
 	fmt.Println(enc[0].DecryptLWE(ctOut))
 	// Output:
 	// 3
@@ -0,0 +1,140 @@
+package tfhe
+
+import (
+	"github.com/sp301415/tfhe-go/math/num"
+)
+
+// AutomorphismGLWE returns the automorphism X -> X^d of ct,
+// which is encrypted with GLWEKey.
+//
+// Panics when ak has no key for d.
+func (e *Evaluator[T]) AutomorphismGLWE(ct GLWECiphertext[T], d int, ak AutomorphismKey[T]) GLWECiphertext[T] {
+	ctOut := NewGLWECiphertext(e.Parameters)
+	e.AutomorphismGLWEAssign(ct, d, ak, ctOut)
+	return ctOut
+}
+
+// AutomorphismGLWEAssign computes the automorphism X -> X^d of ct and writes it to ctOut,
+// which is encrypted with GLWEKey.
+// ct and ctOut may overlap.
+//
+// Panics when ak has no key for d.
+func (e *Evaluator[T]) AutomorphismGLWEAssign(ct GLWECiphertext[T], d int, ak AutomorphismKey[T], ctOut GLWECiphertext[T]) {
+	ksk, ok := ak.Value[d&(2*e.Parameters.polyDegree-1)]
+	if !ok {
+		panic("AutomorphismKey for d not found")
+	}
+
+	if e.buffer.ctPermute.Value == nil {
+		e.buffer.ctPermute = NewGLWECiphertext(e.Parameters)
+	}
+
+	e.PermuteGLWEAssign(ct, d, e.buffer.ctPermute)
+	e.KeySwitchGLWEAssign(e.buffer.ctPermute, ksk, ctOut)
+}
+
+// PackLWEToGLWETrace packs LWE ciphertexts to a GLWE ciphertext using automorphisms,
+// following the algorithm of https://eprint.iacr.org/2020/015.
+// Let n be len(cts) rounded up to a power of two.
+// Then the coefficient of X^(i * PolyDegree / n) of the output encrypts the message of cts[i],
+// and all other coefficients encrypt zero.
+//
+// Input ciphertexts should be of length GLWEDimension + 1, encrypted with LWELargeKey.
+// ak should have keys for [AutomorphismIndicesForTrace],
+// and the output ciphertext is encrypted with GLWEKey.
+//
+// Panics when len(cts) is larger than PolyDegree.
+func (e *Evaluator[T]) PackLWEToGLWETrace(cts []LWECiphertext[T], ak AutomorphismKey[T]) GLWECiphertext[T] {
+	ctOut := NewGLWECiphertext(e.Parameters)
+	e.PackLWEToGLWETraceAssign(cts, ak, ctOut)
+	return ctOut
+}
+
+// PackLWEToGLWETraceAssign packs LWE ciphertexts to a GLWE ciphertext using automorphisms and writes it to ctOut,
+// following the algorithm of https://eprint.iacr.org/2020/015.
+// Let n be len(cts) rounded up to a power of two.
+// Then the coefficient of X^(i * PolyDegree / n) of ctOut encrypts the message of cts[i],
+// and all other coefficients encrypt zero.
+//
+// Input ciphertexts should be of length GLWEDimension + 1, encrypted with LWELargeKey.
+// ak should have keys for [AutomorphismIndicesForTrace],
+// and the output ciphertext is encrypted with GLWEKey.
+//
+// Panics when len(cts) is larger than PolyDegree.
+func (e *Evaluator[T]) PackLWEToGLWETraceAssign(cts []LWECiphertext[T], ak AutomorphismKey[T], ctOut GLWECiphertext[T]) {
+	if len(cts) > e.Parameters.polyDegree {
+		panic("LWE ciphertexts length larger than PolyDegree")
+	}
+
+	if e.buffer.ctPackOdd == nil {
+		e.buffer.ctPackOdd = make([]GLWECiphertext[T], e.Parameters.logPolyDegree)
+		for i := range e.buffer.ctPackOdd {
+			e.buffer.ctPackOdd[i] = NewGLWECiphertext(e.Parameters)
+		}
+		e.buffer.ctPackMono = NewGLWECiphertext(e.Parameters)
+	}
+
+	logCount := 0
+	for 1<<logCount < len(cts) {
+		logCount++
+	}
+
+	e.packLWEToGLWETraceAssign(cts, 0, 1, logCount, ak, ctOut)
+
+	// The field trace clears coefficients other than multiples of X^(PolyDegree / n).
+	for l := logCount + 1; l <= e.Parameters.logPolyDegree; l++ {
+		e.AutomorphismGLWEAssign(ctOut, 1<<l+1, ak, e.buffer.ctPackMono)
+		e.AddGLWEAssign(ctOut, e.buffer.ctPackMono, ctOut)
+	}
+}
+
+// packLWEToGLWETraceAssign packs 2^l ciphertexts cts[r], cts[r+s], ..., cts[r+(2^l-1)s] and writes it to ctOut.
+// Missing ciphertexts are regarded as encryptions of zero.
+// The output encrypts 2^l * sum_i m_i X^(i * PolyDegree / 2^l), where m_i is the message of cts[r+is].
+func (e *Evaluator[T]) packLWEToGLWETraceAssign(cts []LWECiphertext[T], r, s, l int, ak AutomorphismKey[T], ctOut GLWECiphertext[T]) {
+	if l == 0 {
+		if r < len(cts) {
+			e.embedLWEToGLWEAssign(cts[r], ctOut)
+		} else {
+			ctOut.Clear()
+		}
+		return
+	}
+
+	e.packLWEToGLWETraceAssign(cts, r, 2*s, l-1, ak, ctOut)
+
+	if r+s >= len(cts) {
+		e.AutomorphismGLWEAssign(ctOut, 1<<l+1, ak, e.buffer.ctPackMono)
+		e.AddGLWEAssign(ctOut, e.buffer.ctPackMono, ctOut)
+		return
+	}
+
+	ctOdd := e.buffer.ctPackOdd[l-1]
+	e.packLWEToGLWETraceAssign(cts, r+s, 2*s, l-1, ak, ctOdd)
+
+	// ctOut = (ctEven + X^(N/2^l) ctOdd) + Automorphism(ctEven - X^(N/2^l) ctOdd, 2^l + 1)
+	e.MonomialMulGLWEAssign(ctOdd, e.Parameters.polyDegree>>l, e.buffer.ctPackMono)
+	e.SubGLWEAssign(ctOut, e.buffer.ctPackMono, ctOdd)
+	e.AddGLWEAssign(ctOut, e.buffer.ctPackMono, ctOut)
+	e.AutomorphismGLWEAssign(ctOdd, 1<<l+1, ak, e.buffer.ctPackMono)
+	e.AddGLWEAssign(ctOut, e.buffer.ctPackMono, ctOut)
+}
+
+// embedLWEToGLWEAssign embeds ct to a GLWE ciphertext whose constant term encrypts the message of ct divided by PolyDegree,
+// and writes it to ctOut.
+// The division compensates the factor PolyDegree from packing.
+func (e *Evaluator[T]) embedLWEToGLWEAssign(ct LWECiphertext[T], ctOut GLWECiphertext[T]) {
+	polyDegree := e.Parameters.polyDegree
+	logPolyDegree := e.Parameters.logPolyDegree
+
+	ctOut.Value[0].Clear()
+	ctOut.Value[0].Coeffs[0] = num.DivRoundBits(ct.Value[0], logPolyDegree)
+
+	for i := 0; i < e.Parameters.glweRank; i++ {
+		mask := ct.Value[1+i*polyDegree : 1+(i+1)*polyDegree]
+		ctOut.Value[i+1].Coeffs[0] = num.DivRoundBits(mask[0], logPolyDegree)
+		for j := 1; j < polyDegree; j++ {
+			ctOut.Value[i+1].Coeffs[polyDegree-j] = -num.DivRoundBits(mask[j], logPolyDegree)
+		}
+	}
+}
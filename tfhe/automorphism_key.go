@@ -0,0 +1,84 @@
+package tfhe
+
+import (
+	"sort"
+)
+
+// AutomorphismKey is a set of GLWE keyswitch keys for automorphisms X -> X^d.
+// The keyswitch key for d switches GLWEKey(X^d) to GLWEKey,
+// so that the automorphism of a GLWE ciphertext is decryptable under GLWEKey.
+type AutomorphismKey[T TorusInt] struct {
+	GadgetParameters GadgetParameters[T]
+
+	// Value maps d in [0, 2*PolyDegree) to the keyswitch key for X -> X^d.
+	Value map[int]GLWEKeySwitchKey[T]
+}
+
+// NewAutomorphismKey creates a new AutomorphismKey for automorphisms X -> X^d for d in idx.
+//
+// Panics when any d is not odd.
+func NewAutomorphismKey[T TorusInt](params Parameters[T], idx []int, gadgetParams GadgetParameters[T]) AutomorphismKey[T] {
+	return NewAutomorphismKeyCustom(params.glweRank, params.polyDegree, idx, gadgetParams)
+}
+
+// NewAutomorphismKeyCustom creates a new AutomorphismKey with custom parameters.
+//
+// Panics when any d is not odd.
+func NewAutomorphismKeyCustom[T TorusInt](glweRank, polyDegree int, idx []int, gadgetParams GadgetParameters[T]) AutomorphismKey[T] {
+	ak := make(map[int]GLWEKeySwitchKey[T], len(idx))
+	for _, d := range idx {
+		if d&1 == 0 {
+			panic("d not odd")
+		}
+		ak[d&(2*polyDegree-1)] = NewGLWEKeySwitchKeyCustom(glweRank, glweRank, polyDegree, gadgetParams)
+	}
+	return AutomorphismKey[T]{Value: ak, GadgetParameters: gadgetParams}
+}
+
+// AutomorphismIndicesForTrace returns the automorphism indices used by [*Evaluator.PackLWEToGLWETrace],
+// which are 2^i + 1 for 1 <= i <= log(PolyDegree).
+func AutomorphismIndicesForTrace[T TorusInt](params Parameters[T]) []int {
+	idx := make([]int, params.logPolyDegree)
+	for i := range idx {
+		idx[i] = 1<<(i+1) + 1
+	}
+	return idx
+}
+
+// Indices returns the automorphism indices of this key in ascending order.
+func (ak AutomorphismKey[T]) Indices() []int {
+	idx := make([]int, 0, len(ak.Value))
+	for d := range ak.Value {
+		idx = append(idx, d)
+	}
+	sort.Ints(idx)
+	return idx
+}
+
+// Copy returns a copy of the key.
+func (ak AutomorphismKey[T]) Copy() AutomorphismKey[T] {
+	akCopy := make(map[int]GLWEKeySwitchKey[T], len(ak.Value))
+	for d, ksk := range ak.Value {
+		akCopy[d] = ksk.Copy()
+	}
+	return AutomorphismKey[T]{Value: akCopy, GadgetParameters: ak.GadgetParameters}
+}
+
+// CopyFrom copies values from key.
+// Keys for automorphism indices not in ak are ignored.
+func (ak *AutomorphismKey[T]) CopyFrom(akIn AutomorphismKey[T]) {
+	for d, ksk := range ak.Value {
+		if kskIn, ok := akIn.Value[d]; ok {
+			ksk.CopyFrom(kskIn)
+			ak.Value[d] = ksk
+		}
+	}
+	ak.GadgetParameters = akIn.GadgetParameters
+}
+
+// Clear clears the key.
+func (ak *AutomorphismKey[T]) Clear() {
+	for _, ksk := range ak.Value {
+		ksk.Clear()
+	}
+}
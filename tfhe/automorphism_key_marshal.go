@@ -0,0 +1,206 @@
+package tfhe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/math/poly"
+)
+
+// ByteSize returns the size of the key in bytes.
+func (ak AutomorphismKey[T]) ByteSize() int {
+	glweRank, polyDegree := ak.glweShape()
+	return 40 + len(ak.Value)*(8+glweRank*ak.GadgetParameters.level*(glweRank+1)*polyDegree*8)
+}
+
+// glweShape returns the GLWERank and PolyDegree of the keyswitch keys.
+// Both are zero if the key is empty.
+func (ak AutomorphismKey[T]) glweShape() (glweRank, polyDegree int) {
+	for _, ksk := range ak.Value {
+		return ksk.InputGLWERank(), ksk.Value[0].Value[0].Value[0].Degree()
+	}
+	return 0, 0
+}
+
+// headerWriteTo writes the header.
+func (ak AutomorphismKey[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var buf [8]byte
+
+	base := ak.GadgetParameters.base
+	binary.BigEndian.PutUint64(buf[:], uint64(base))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	level := ak.GadgetParameters.level
+	binary.BigEndian.PutUint64(buf[:], uint64(level))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	count := len(ak.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(count))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	glweRank, polyDegree := ak.glweShape()
+
+	binary.BigEndian.PutUint64(buf[:], uint64(glweRank))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	binary.BigEndian.PutUint64(buf[:], uint64(polyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (ak AutomorphismKey[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	for _, d := range ak.Indices() {
+		binary.BigEndian.PutUint64(buf[:], uint64(d))
+		if nWrite, err = w.Write(buf[:]); err != nil {
+			return n + int64(nWrite), err
+		}
+		n += int64(nWrite)
+
+		if nWrite64, err = ak.Value[d].valueWriteTo(w); err != nil {
+			return n + nWrite64, err
+		}
+		n += nWrite64
+	}
+
+	return
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] Base
+//	[8] Level
+//	[8] Count
+//	[8] GLWERank
+//	[8] PolyDegree
+//	    Value
+//
+// Value consists of Count pairs of [8] d and the keyswitch key for d, in ascending order of d.
+func (ak AutomorphismKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = ak.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = ak.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(ak.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (ak *AutomorphismKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	base := T(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	level := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	count := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	glweRank := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	polyDegree := int(binary.BigEndian.Uint64(buf[:]))
+
+	// There are PolyDegree odd d in [0, 2 * PolyDegree),
+	// so count is checked before allocating the map.
+	switch {
+	case polyDegree < poly.MinDegree || !num.IsPowerOfTwo(polyDegree):
+		return n, errors.New("PolyDegree not valid")
+	case count < 0 || count > polyDegree:
+		return n, errors.New("Count larger than PolyDegree")
+	}
+
+	gadgetParams := GadgetParametersLiteral[T]{Base: base, Level: level}.Compile()
+	*ak = AutomorphismKey[T]{Value: make(map[int]GLWEKeySwitchKey[T], count), GadgetParameters: gadgetParams}
+
+	for i := 0; i < count; i++ {
+		if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+			return n + int64(nRead), err
+		}
+		n += int64(nRead)
+		d := int(binary.BigEndian.Uint64(buf[:]))
+		if d&1 == 0 || d >= 2*polyDegree {
+			return n, errors.New("d not valid")
+		}
+
+		ksk := NewGLWEKeySwitchKeyCustom(glweRank, glweRank, polyDegree, gadgetParams)
+		if nRead64, err = ksk.valueReadFrom(r); err != nil {
+			return n + nRead64, err
+		}
+		n += nRead64
+		ak.Value[d] = ksk
+	}
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (ak AutomorphismKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, ak.ByteSize()))
+	_, err = ak.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (ak *AutomorphismKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := ak.ReadFrom(buf)
+	return err
+}
@@ -0,0 +1,26 @@
+package tfhe
+
+// GenAutomorphismKey samples a new automorphism key for automorphisms X -> X^d for d in idx.
+//
+// Panics when any d is not odd.
+func (e *Encryptor[T]) GenAutomorphismKey(idx []int, gadgetParams GadgetParameters[T]) AutomorphismKey[T] {
+	ak := NewAutomorphismKey(e.Parameters, idx, gadgetParams)
+
+	skIn := NewGLWESecretKey(e.Parameters)
+	for d, ksk := range ak.Value {
+		for i := 0; i < e.Parameters.glweRank; i++ {
+			e.PolyEvaluator.PermutePolyAssign(e.SecretKey.GLWEKey.Value[i], d, skIn.Value[i])
+		}
+		for i := 0; i < ksk.InputGLWERank(); i++ {
+			e.EncryptFourierGLevPolyAssign(skIn.Value[i], ksk.Value[i])
+		}
+	}
+
+	return ak
+}
+
+// GenAutomorphismKeyForTrace samples a new automorphism key
+// for indices of [AutomorphismIndicesForTrace], used for [*Evaluator.PackLWEToGLWETrace].
+func (e *Encryptor[T]) GenAutomorphismKeyForTrace(gadgetParams GadgetParameters[T]) AutomorphismKey[T] {
+	return e.GenAutomorphismKey(AutomorphismIndicesForTrace(e.Parameters), gadgetParams)
+}
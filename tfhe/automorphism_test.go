@@ -0,0 +1,107 @@
+package tfhe_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomorphism(t *testing.T) {
+	gadgetParams := tfhe.GadgetParametersLiteral[uint64]{Base: 1 << 12, Level: 4}.Compile()
+	ak := enc.GenAutomorphismKeyForTrace(gadgetParams)
+
+	messageModulus := int(params.MessageModulus())
+
+	t.Run("AutomorphismGLWE", func(t *testing.T) {
+		messages := make([]int, params.PolyDegree())
+		for i := range messages {
+			messages[i] = (3*i + 1) % messageModulus
+		}
+		ct := enc.EncryptGLWE(messages)
+
+		permuted := func(d int) []int {
+			pt := enc.EncodeGLWE(messages)
+			pt.Value = eval.PolyEvaluator.PermutePoly(pt.Value, d)
+			return enc.DecodeGLWE(pt)
+		}
+
+		for _, d := range []int{3, 2*params.PolyDegree() - 1} {
+			akD := enc.GenAutomorphismKey([]int{d}, gadgetParams)
+			assert.Equal(t, permuted(d), enc.DecryptGLWE(eval.AutomorphismGLWE(ct, d, akD)))
+		}
+
+		// Automorphism indices are taken modulo 2 * PolyDegree.
+		d := 2*params.PolyDegree() + 5
+		assert.Equal(t, permuted(d), enc.DecryptGLWE(eval.AutomorphismGLWE(ct, d, ak)))
+	})
+
+	t.Run("PackLWEToGLWETrace", func(t *testing.T) {
+		for _, count := range []int{1, 5, params.PolyDegree()} {
+			messages := make([]int, count)
+			cts := make([]tfhe.LWECiphertext[uint64], count)
+			for i := range cts {
+				messages[i] = (5*i + 2) % messageModulus
+				cts[i] = enc.EncryptLWE(messages[i])
+			}
+
+			stride := params.PolyDegree()
+			for i := 1; i < count; i *= 2 {
+				stride /= 2
+			}
+
+			messagesOut := enc.DecryptGLWE(eval.PackLWEToGLWETrace(cts, ak))
+			messagesPacked := make([]int, params.PolyDegree())
+			for i := range messages {
+				messagesPacked[i*stride] = messages[i]
+			}
+			assert.Equal(t, messagesPacked, messagesOut, "count=%v", count)
+		}
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := ak.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, ak.ByteSize(), len(data))
+
+		var akOut tfhe.AutomorphismKey[uint64]
+		assert.NoError(t, akOut.UnmarshalBinary(data))
+		assert.Equal(t, ak, akOut)
+		assert.Equal(t, tfhe.AutomorphismIndicesForTrace(params), akOut.Indices())
+
+		// Malformed headers return errors before allocating.
+		for _, header := range []struct {
+			offset int
+			value  uint64
+		}{
+			{16, uint64(params.PolyDegree() + 1)}, // Count
+			{16, 1 << 62},                         // Count
+			{16, 1<<64 - 1},                       // Count
+			{32, 1000},                            // PolyDegree
+		} {
+			dataMalformed := append([]byte(nil), data...)
+			binary.BigEndian.PutUint64(dataMalformed[header.offset:], header.value)
+			var akMalformed tfhe.AutomorphismKey[uint64]
+			assert.NotPanics(t, func() { assert.Error(t, akMalformed.UnmarshalBinary(dataMalformed)) })
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		akCopy := ak.Copy()
+		assert.Equal(t, ak, akCopy)
+
+		akCopy.Clear()
+		assert.NotEqual(t, ak, akCopy)
+
+		akCopy.CopyFrom(ak)
+		assert.Equal(t, ak, akCopy)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ct := enc.EncryptGLWE([]int{1})
+		assert.Panics(t, func() { eval.AutomorphismGLWE(ct, 7, ak) })
+		assert.Panics(t, func() { enc.GenAutomorphismKey([]int{2}, gadgetParams) })
+		assert.Panics(t, func() { eval.PackLWEToGLWETrace(make([]tfhe.LWECiphertext[uint64], params.PolyDegree()+1), ak) })
+	})
+}
@@ -0,0 +1,248 @@
+package tfhe
+
+import (
+	"sync"
+
+	"github.com/sp301415/tfhe-go/math/num"
+)
+
+// BatchEvaluator bootstraps multiple LWE ciphertexts concurrently.
+// It holds a shallow copy of the Evaluator for each worker,
+// which is reused across calls.
+//
+// BatchEvaluator is not safe for concurrent use.
+// Use [*BatchEvaluator.ShallowCopy] to get a safe copy.
+type BatchEvaluator[T TorusInt] struct {
+	// Evaluators are the evaluators for each worker.
+	Evaluators []*Evaluator[T]
+}
+
+// NewBatchEvaluator creates a new BatchEvaluator with workerCount workers,
+// each using a shallow copy of eval.
+// Usually, workerCount is set to runtime.NumCPU().
+//
+// Panics when workerCount is smaller than one.
+func NewBatchEvaluator[T TorusInt](eval *Evaluator[T], workerCount int) *BatchEvaluator[T] {
+	if workerCount < 1 {
+		panic("workerCount smaller than one")
+	}
+
+	evaluators := make([]*Evaluator[T], workerCount)
+	for i := range evaluators {
+		evaluators[i] = eval.ShallowCopy()
+	}
+
+	return &BatchEvaluator[T]{
+		Evaluators: evaluators,
+	}
+}
+
+// ShallowCopy returns a shallow copy of this BatchEvaluator.
+// Returned BatchEvaluator is safe for concurrent use.
+func (e *BatchEvaluator[T]) ShallowCopy() *BatchEvaluator[T] {
+	evaluators := make([]*Evaluator[T], len(e.Evaluators))
+	for i := range evaluators {
+		evaluators[i] = e.Evaluators[i].ShallowCopy()
+	}
+
+	return &BatchEvaluator[T]{
+		Evaluators: evaluators,
+	}
+}
+
+// WorkerCount returns the number of workers of this BatchEvaluator.
+func (e *BatchEvaluator[T]) WorkerCount() int {
+	return len(e.Evaluators)
+}
+
+// newBatchOutput allocates an LWE ciphertext for each input.
+func (e *BatchEvaluator[T]) newBatchOutput(cts []LWECiphertext[T]) []LWECiphertext[T] {
+	ctsOut := make([]LWECiphertext[T], len(cts))
+	for i := range ctsOut {
+		ctsOut[i] = NewLWECiphertext(e.Evaluators[0].Parameters)
+	}
+	return ctsOut
+}
+
+// BootstrapLUTBatch returns bootstrapped LWE ciphertexts with respect to given LUT.
+func (e *BatchEvaluator[T]) BootstrapLUTBatch(cts []LWECiphertext[T], lut LookUpTable[T]) []LWECiphertext[T] {
+	ctsOut := e.newBatchOutput(cts)
+	e.BootstrapLUTBatchAssign(cts, lut, ctsOut)
+	return ctsOut
+}
+
+// BootstrapLUTBatchAssign bootstraps LWE ciphertexts with respect to given LUT and writes them to ctsOut.
+// cts[i] and ctsOut[i] may be the same.
+//
+// Panics when len(ctsOut) is not equal to len(cts).
+func (e *BatchEvaluator[T]) BootstrapLUTBatchAssign(cts []LWECiphertext[T], lut LookUpTable[T], ctsOut []LWECiphertext[T]) {
+	if len(ctsOut) != len(cts) {
+		panic("ctsOut length not equal to cts length")
+	}
+
+	runBatch(len(e.Evaluators), len(cts), func(w, i int) {
+		e.Evaluators[w].BootstrapLUTAssign(cts[i], lut, ctsOut[i])
+	})
+}
+
+// BootstrapFullDomainBatch returns full-domain bootstrapped LWE ciphertexts
+// with respect to given compress LUT and evaluation LUT.
+func (e *BatchEvaluator[T]) BootstrapFullDomainBatch(cts []LWECiphertext[T], lutCompress, lutEval LookUpTable[T]) []LWECiphertext[T] {
+	ctsOut := e.newBatchOutput(cts)
+	e.BootstrapFullDomainBatchAssign(cts, lutCompress, lutEval, ctsOut)
+	return ctsOut
+}
+
+// BootstrapFullDomainBatchAssign full-domain bootstraps LWE ciphertexts
+// with respect to given compress LUT and evaluation LUT, and writes them to ctsOut.
+// cts[i] and ctsOut[i] may be the same.
+//
+// Panics when len(ctsOut) is not equal to len(cts).
+func (e *BatchEvaluator[T]) BootstrapFullDomainBatchAssign(cts []LWECiphertext[T], lutCompress, lutEval LookUpTable[T], ctsOut []LWECiphertext[T]) {
+	if len(ctsOut) != len(cts) {
+		panic("ctsOut length not equal to cts length")
+	}
+
+	runBatch(len(e.Evaluators), len(cts), func(w, i int) {
+		e.Evaluators[w].BootstrapFullDomainAssign(cts[i], lutCompress, lutEval, ctsOut[i])
+	})
+}
+
+// FDFBLUTBatch returns LWE ciphertexts bootstrapped by [*Evaluator.FDFBLUTAssign].
+func (e *BatchEvaluator[T]) FDFBLUTBatch(cts []LWECiphertext[T], compressLUT, fdfbLUT LookUpTable[T]) []LWECiphertext[T] {
+	ctsOut := e.newBatchOutput(cts)
+	e.FDFBLUTBatchAssign(cts, compressLUT, fdfbLUT, ctsOut)
+	return ctsOut
+}
+
+// FDFBLUTBatchAssign bootstraps LWE ciphertexts by [*Evaluator.FDFBLUTAssign] and writes them to ctsOut.
+// cts[i] and ctsOut[i] may be the same.
+//
+// Panics when len(ctsOut) is not equal to len(cts).
+func (e *BatchEvaluator[T]) FDFBLUTBatchAssign(cts []LWECiphertext[T], compressLUT, fdfbLUT LookUpTable[T], ctsOut []LWECiphertext[T]) {
+	if len(ctsOut) != len(cts) {
+		panic("ctsOut length not equal to cts length")
+	}
+
+	runBatch(len(e.Evaluators), len(cts), func(w, i int) {
+		e.Evaluators[w].FDFBLUTAssign(cts[i], compressLUT, fdfbLUT, ctsOut[i])
+	})
+}
+
+// FullDomainBatchEvaluator bootstraps multiple LWE ciphertexts concurrently
+// using the evaluator hierarchy.
+// It holds a shallow copy of the FullDomainEvaluator for each worker,
+// which is reused across calls.
+//
+// FullDomainBatchEvaluator is not safe for concurrent use.
+// Use [*FullDomainBatchEvaluator.ShallowCopy] to get a safe copy.
+type FullDomainBatchEvaluator[T TorusInt] struct {
+	// Evaluators are the evaluators for each worker.
+	Evaluators []*FullDomainEvaluator[T]
+}
+
+// NewFullDomainBatchEvaluator creates a new FullDomainBatchEvaluator with workerCount workers,
+// each using a shallow copy of eval.
+// Usually, workerCount is set to runtime.NumCPU().
+//
+// Panics when workerCount is smaller than one.
+func NewFullDomainBatchEvaluator[T TorusInt](eval *FullDomainEvaluator[T], workerCount int) *FullDomainBatchEvaluator[T] {
+	if workerCount < 1 {
+		panic("workerCount smaller than one")
+	}
+
+	evaluators := make([]*FullDomainEvaluator[T], workerCount)
+	for i := range evaluators {
+		evaluators[i] = eval.ShallowCopy()
+	}
+
+	return &FullDomainBatchEvaluator[T]{
+		Evaluators: evaluators,
+	}
+}
+
+// ShallowCopy returns a shallow copy of this FullDomainBatchEvaluator.
+// Returned FullDomainBatchEvaluator is safe for concurrent use.
+func (e *FullDomainBatchEvaluator[T]) ShallowCopy() *FullDomainBatchEvaluator[T] {
+	evaluators := make([]*FullDomainEvaluator[T], len(e.Evaluators))
+	for i := range evaluators {
+		evaluators[i] = e.Evaluators[i].ShallowCopy()
+	}
+
+	return &FullDomainBatchEvaluator[T]{
+		Evaluators: evaluators,
+	}
+}
+
+// WorkerCount returns the number of workers of this FullDomainBatchEvaluator.
+func (e *FullDomainBatchEvaluator[T]) WorkerCount() int {
+	return len(e.Evaluators)
+}
+
+// BootstrapFuncBatch returns bootstrapped LWE ciphertexts with respect to given function.
+func (e *FullDomainBatchEvaluator[T]) BootstrapFuncBatch(cts []LWECiphertext[T], f func(int) int) []LWECiphertext[T] {
+	e.Evaluators[0].GenDecomposedLUTAssign(f, &e.Evaluators[0].buffer.lut)
+	return e.BootstrapLUTBatch(cts, e.Evaluators[0].buffer.lut)
+}
+
+// BootstrapFuncBatchAssign bootstraps LWE ciphertexts with respect to given function and writes them to ctsOut.
+// cts[i] and ctsOut[i] may be the same.
+//
+// Panics when len(ctsOut) is not equal to len(cts).
+func (e *FullDomainBatchEvaluator[T]) BootstrapFuncBatchAssign(cts []LWECiphertext[T], f func(int) int, ctsOut []LWECiphertext[T]) {
+	e.Evaluators[0].GenDecomposedLUTAssign(f, &e.Evaluators[0].buffer.lut)
+	e.BootstrapLUTBatchAssign(cts, e.Evaluators[0].buffer.lut, ctsOut)
+}
+
+// BootstrapLUTBatch returns bootstrapped LWE ciphertexts with respect to given decomposed LUT.
+func (e *FullDomainBatchEvaluator[T]) BootstrapLUTBatch(cts []LWECiphertext[T], lut DecomposedLookUpTable[T]) []LWECiphertext[T] {
+	ctsOut := make([]LWECiphertext[T], len(cts))
+	for i := range ctsOut {
+		ctsOut[i] = NewLWECiphertext(e.Evaluators[0].Parameters)
+	}
+	e.BootstrapLUTBatchAssign(cts, lut, ctsOut)
+	return ctsOut
+}
+
+// BootstrapLUTBatchAssign bootstraps LWE ciphertexts with respect to given decomposed LUT and writes them to ctsOut.
+// cts[i] and ctsOut[i] may be the same.
+//
+// Panics when len(ctsOut) is not equal to len(cts),
+// or when lut was not built for the parameters of this FullDomainBatchEvaluator.
+func (e *FullDomainBatchEvaluator[T]) BootstrapLUTBatchAssign(cts []LWECiphertext[T], lut DecomposedLookUpTable[T], ctsOut []LWECiphertext[T]) {
+	if len(ctsOut) != len(cts) {
+		panic("ctsOut length not equal to cts length")
+	}
+	checkDecomposedLUT(lut, e.Evaluators[0].Parameters, false)
+
+	runBatch(len(e.Evaluators), len(cts), func(w, i int) {
+		e.Evaluators[w].BootstrapLUTAssign(cts[i], lut, ctsOut[i])
+	})
+}
+
+// runBatch calls job(w, i) for each i in [0, n),
+// distributing them across workerCount goroutines.
+// w is the index of the worker calling the job.
+func runBatch(workerCount, n int, job func(w, i int)) {
+	workerCount = num.Min(workerCount, n)
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < n; i++ {
+			jobs <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+	for w := 0; w < workerCount; w++ {
+		go func(w int) {
+			for i := range jobs {
+				job(w, i)
+			}
+			wg.Done()
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,126 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchEvaluator(t *testing.T) {
+	params := tfhe.Params5.Compile()
+	messageModulus := int(params.MessageModulus())
+
+	enc := tfhe.NewEncryptor(params)
+	eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+	batchEval := tfhe.NewBatchEvaluator(eval, 3)
+
+	f := func(x int) int { return 3*x + 1 }
+	messages := []int{0, 1, messageModulus/2 - 1, messageModulus / 2, messageModulus - 1}
+	cts := make([]tfhe.LWECiphertext[uint64], len(messages))
+	for i, x := range messages {
+		cts[i] = enc.EncryptLWE(x)
+	}
+
+	t.Run("BootstrapLUTBatch", func(t *testing.T) {
+		lut := eval.GenLookUpTable(func(x int) int { return x + 1 })
+		ctsOut := batchEval.BootstrapLUTBatch(cts, lut)
+		for i := range ctsOut {
+			assert.Equal(t, enc.DecryptLWE(eval.BootstrapLUT(cts[i], lut)), enc.DecryptLWE(ctsOut[i]))
+		}
+	})
+
+	compressLUT := tfhe.NewLookUpTable(params)
+	eval.GenExtendedCompressLUTAssign(compressLUT)
+	fdfbLUT := tfhe.NewLookUpTable(params)
+	eval.GenExtendedFDFBLookUpTableAssign(f, fdfbLUT)
+
+	t.Run("BootstrapFullDomainBatch", func(t *testing.T) {
+		ctsOut := batchEval.BootstrapFullDomainBatch(cts, compressLUT, fdfbLUT)
+		for i := range ctsOut {
+			assert.Equal(t, f(messages[i])%messageModulus, enc.DecryptLWE(ctsOut[i]))
+		}
+	})
+
+	t.Run("FDFBLUTBatchAssign", func(t *testing.T) {
+		ctsOut := make([]tfhe.LWECiphertext[uint64], len(cts))
+		for i := range ctsOut {
+			ctsOut[i] = cts[i].Copy()
+		}
+		batchEval.ShallowCopy().FDFBLUTBatchAssign(ctsOut, compressLUT, fdfbLUT, ctsOut)
+		for i := range ctsOut {
+			assert.Equal(t, f(messages[i])%messageModulus, enc.DecryptLWE(ctsOut[i]))
+		}
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { tfhe.NewBatchEvaluator(eval, 0) })
+		assert.Panics(t, func() { batchEval.FDFBLUTBatchAssign(cts, compressLUT, fdfbLUT, cts[:1]) })
+	})
+}
+
+func TestFullDomainBatchEvaluator(t *testing.T) {
+	params := tfhe.Params3Uint32.Compile()
+	messageModulus := int(params.MessageModulus())
+
+	enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+	eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+	batchEval := tfhe.NewFullDomainBatchEvaluator(eval, runtime.NumCPU())
+	assert.Equal(t, runtime.NumCPU(), batchEval.WorkerCount())
+
+	cts := make([]tfhe.LWECiphertext[uint32], 2*messageModulus)
+	for i := range cts {
+		cts[i] = enc[0].EncryptLWE(i % messageModulus)
+	}
+
+	f := func(x int) int { return 5*x + 2 }
+	for _, batchSize := range []int{0, 1, len(cts)} {
+		ctsOut := batchEval.BootstrapFuncBatch(cts[:batchSize], f)
+		assert.Equal(t, batchSize, len(ctsOut))
+		for i := range ctsOut {
+			assert.Equal(t, f(i%messageModulus)%messageModulus, enc[0].DecryptLWE(ctsOut[i]))
+		}
+	}
+
+	assert.Panics(t, func() { tfhe.NewFullDomainBatchEvaluator(eval, -1) })
+	assert.Panics(t, func() {
+		batchEval.BootstrapLUTBatchAssign(cts, tfhe.NewDecomposedLookUpTable(tfhe.Params4Uint32.Compile()), cts)
+	})
+}
+
+func Benchmark_OurFDFBBatch(b *testing.B) {
+	batchSize := 2 * runtime.NumCPU()
+
+	for _, params := range paramsListNew {
+		params := params.Compile()
+
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		batchEval := tfhe.NewFullDomainBatchEvaluator(eval, runtime.NumCPU())
+
+		lut := eval.GenDecomposedLUT(func(x int) int { return 13 + x })
+		cts := make([]tfhe.LWECiphertext[uint64], batchSize)
+		ctsOut := make([]tfhe.LWECiphertext[uint64], batchSize)
+		for i := range cts {
+			cts[i] = enc[0].EncryptLWE(i)
+			ctsOut[i] = tfhe.NewLWECiphertext(params)
+		}
+
+		b.Run(fmt.Sprintf("prec=%v/serial", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range cts {
+					eval.BootstrapLUTAssign(cts[j], lut, ctsOut[j])
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("prec=%v/batch", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				batchEval.BootstrapLUTBatchAssign(cts, lut, ctsOut)
+			}
+		})
+	}
+}
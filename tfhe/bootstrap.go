@@ -3,6 +3,7 @@ package tfhe
 import (
 	"math"
 
+	"github.com/sp301415/tfhe-go/math/num"
 	"github.com/sp301415/tfhe-go/math/vec"
 )
 
@@ -395,7 +396,14 @@ func (e *Evaluator[T]) KeySwitchForBootstrap(ct LWECiphertext[T]) LWECiphertext[
 // KeySwitchForBootstrapAssign performs the keyswitching using evaulater's evaluation key.
 // Input ciphertext should be of length GLWEDimension + 1.
 // Output ciphertext should be of length LWEDimension + 1.
+//
+// If KeySwitchMethod is KeySwitchMethodGLWE, this uses GLWEKeySwitchKey of the evaluation key.
 func (e *Evaluator[T]) KeySwitchForBootstrapAssign(ct, ctOut LWECiphertext[T]) {
+	if e.Parameters.keySwitchMethod == KeySwitchMethodGLWE {
+		e.keySwitchForBootstrapGLWEAssign(ct, ctOut)
+		return
+	}
+
 	scalarDecomposed := e.Decomposer.buffer.scalarDecomposed[:e.Parameters.keySwitchParameters.level]
 
 	vec.CopyAssign(ct.Value[:e.Parameters.lweDimension+1], ctOut.Value)
@@ -407,6 +415,57 @@ func (e *Evaluator[T]) KeySwitchForBootstrapAssign(ct, ctOut LWECiphertext[T]) {
 	}
 }
 
+// keySwitchForBootstrapGLWEAssign performs the keyswitching using GLWEKeySwitchKey.
+//
+// The mask a of ct corresponding to the tail of LWELargeKey is packed to polynomials
+//
+//	A_i(X) = a_{iN} - a_{iN+1} X^{N-1} - ... - a_{iN+N-1} X,
+//
+// so that the constant term of sum_i A_i * Z_i equals <a, z>,
+// where Z_i are the tail of LWELargeKey packed to polynomials.
+// After keyswitching A_i with GLWEKeySwitchKey, the constant term is extracted
+// as an LWE ciphertext under LWEKey.
+func (e *Evaluator[T]) keySwitchForBootstrapGLWEAssign(ct, ctOut LWECiphertext[T]) {
+	ksk := e.EvaluationKey.GLWEKeySwitchKey
+	polyDegree := e.Parameters.polyDegree
+	lweDimension := e.Parameters.lweDimension
+
+	vec.CopyAssign(ct.Value[:lweDimension+1], ctOut.Value)
+	if ksk.InputGLWERank() == 0 {
+		return
+	}
+
+	if e.buffer.ctPackedKeySwitch.Value == nil {
+		e.buffer.ctPackedKeySwitch = NewGLWECiphertext(e.Parameters)
+		e.buffer.ctPackedKeySwitchOut = NewGLWECiphertext(e.Parameters)
+	}
+
+	e.buffer.ctPackedKeySwitch.Value[0].Clear()
+	for i := 0; i < ksk.InputGLWERank(); i++ {
+		start := lweDimension + i*polyDegree
+		end := num.Min(start+polyDegree, e.Parameters.glweDimension)
+		mask := ct.Value[1+start : 1+end]
+
+		e.buffer.ctPackedKeySwitch.Value[i+1].Clear()
+		e.buffer.ctPackedKeySwitch.Value[i+1].Coeffs[0] = mask[0]
+		for j := 1; j < len(mask); j++ {
+			e.buffer.ctPackedKeySwitch.Value[i+1].Coeffs[polyDegree-j] = -mask[j]
+		}
+	}
+
+	e.KeySwitchGLWEAssign(e.buffer.ctPackedKeySwitch, ksk, e.buffer.ctPackedKeySwitchOut)
+
+	ctOut.Value[0] += e.buffer.ctPackedKeySwitchOut.Value[0].Coeffs[0]
+	for i := 0; i < lweDimension; i++ {
+		p, j := e.buffer.ctPackedKeySwitchOut.Value[i/polyDegree+1], i%polyDegree
+		if j == 0 {
+			ctOut.Value[i+1] += p.Coeffs[0]
+		} else {
+			ctOut.Value[i+1] -= p.Coeffs[polyDegree-j]
+		}
+	}
+}
+
 func (e *Evaluator[T]) blindRotateWithMSconstAssign(ct LWECiphertext[T], lut LookUpTable[T], MSconst float64, ctOut GLWECiphertext[T]) {
 	polyDecomposed := e.Decomposer.buffer.polyDecomposed[:e.Parameters.blindRotateParameters.level]
 
@@ -445,12 +504,14 @@ func (e *Evaluator[T]) blindRotateWithMSconstAssign(ct LWECiphertext[T], lut Loo
 	}
 }
 
-func (e *Evaluator[T]) BootstrapDecomposedLUTAssign(ct LWECiphertext[T], decomposedLUT []LookUpTable[T], ctOut LWECiphertext[T]) {
+func (e *Evaluator[T]) BootstrapDecomposedLUTAssign(ct LWECiphertext[T], decomposedLUT DecomposedLookUpTable[T], ctOut LWECiphertext[T]) {
+	checkDecomposedLUT(decomposedLUT, e.Parameters, true)
+
 	ctRotateAcc := NewGLWECiphertext(e.Parameters)
 	e.KeySwitchForBootstrapAssign(ct, e.buffer.ctKeySwitchForBootstrap)
 	// Evaluate NegLUT
-	for i := 0; i < len(decomposedLUT)-1; i++ {
-		e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT[i], e.Parameters.polyExtendFactor/(1<<(i+1)), e.buffer.ctRotate)
+	for i := 0; i < len(decomposedLUT.NegLUTs); i++ {
+		e.blindRotateArbitraryExtendedAssign(ct, decomposedLUT.NegLUTs[i], e.Parameters.polyExtendFactor/(1<<(i+1)), e.buffer.ctRotate)
 		e.AddGLWEAssign(ctRotateAcc, e.buffer.ctRotate, ctRotateAcc)
 	}
 	// FDFB
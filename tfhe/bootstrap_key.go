@@ -8,11 +8,24 @@ type EvaluationKey[T TorusInt] struct {
 	// BlindRotateKey is a blindrotate key.
 	BlindRotateKey BlindRotateKey[T]
 	// KeySwitchKey is a keyswitch key switching LWELargeKey -> LWEKey.
+	// This is empty if KeySwitchMethod is KeySwitchMethodGLWE.
 	KeySwitchKey LWEKeySwitchKey[T]
+	// GLWEKeySwitchKey is a keyswitch key switching LWELargeKey -> LWEKey,
+	// where both keys are packed to polynomials.
+	// This is empty unless KeySwitchMethod is KeySwitchMethodGLWE.
+	GLWEKeySwitchKey GLWEKeySwitchKey[T]
 }
 
 // NewEvaluationKey creates a new EvaluationKey.
 func NewEvaluationKey[T TorusInt](params Parameters[T]) EvaluationKey[T] {
+	if params.keySwitchMethod == KeySwitchMethodGLWE {
+		return EvaluationKey[T]{
+			BlindRotateKey:   NewBlindRotateKey(params),
+			KeySwitchKey:     NewLWEKeySwitchKeyCustom(0, 0, params.keySwitchParameters),
+			GLWEKeySwitchKey: NewGLWEKeySwitchKeyForBootstrap(params),
+		}
+	}
+
 	return EvaluationKey[T]{
 		BlindRotateKey: NewBlindRotateKey(params),
 		KeySwitchKey:   NewKeySwitchKeyForBootstrap(params),
@@ -20,6 +33,7 @@ func NewEvaluationKey[T TorusInt](params Parameters[T]) EvaluationKey[T] {
 }
 
 // NewEvaluationKeyCustom creates a new EvaluationKey with custom parameters.
+// The keyswitch key is created for KeySwitchMethodLWE.
 func NewEvaluationKeyCustom[T TorusInt](lweDimension, glweRank, polyDegree int, blindRotateParams, keySwitchParams GadgetParameters[T]) EvaluationKey[T] {
 	return EvaluationKey[T]{
 		BlindRotateKey: NewBlindRotateKeyCustom(lweDimension, glweRank, polyDegree, blindRotateParams),
@@ -30,8 +44,9 @@ func NewEvaluationKeyCustom[T TorusInt](lweDimension, glweRank, polyDegree int,
 // Copy returns a copy of the key.
 func (evk EvaluationKey[T]) Copy() EvaluationKey[T] {
 	return EvaluationKey[T]{
-		BlindRotateKey: evk.BlindRotateKey.Copy(),
-		KeySwitchKey:   evk.KeySwitchKey.Copy(),
+		BlindRotateKey:   evk.BlindRotateKey.Copy(),
+		KeySwitchKey:     evk.KeySwitchKey.Copy(),
+		GLWEKeySwitchKey: evk.GLWEKeySwitchKey.Copy(),
 	}
 }
 
@@ -39,12 +54,64 @@ func (evk EvaluationKey[T]) Copy() EvaluationKey[T] {
 func (evk *EvaluationKey[T]) CopyFrom(evkIn EvaluationKey[T]) {
 	evk.BlindRotateKey.CopyFrom(evkIn.BlindRotateKey)
 	evk.KeySwitchKey.CopyFrom(evkIn.KeySwitchKey)
+	evk.GLWEKeySwitchKey.CopyFrom(evkIn.GLWEKeySwitchKey)
 }
 
 // Clear clears the key.
 func (evk *EvaluationKey[T]) Clear() {
 	evk.BlindRotateKey.Clear()
 	evk.KeySwitchKey.Clear()
+	evk.GLWEKeySwitchKey.Clear()
+}
+
+// HierarchicalEvaluationKey is a public key for the evaluator hierarchy,
+// which consists of EvaluationKeys for each depth.
+// All keys should be treated as read-only.
+// Changing them mid-operation will usually result in wrong results.
+type HierarchicalEvaluationKey[T TorusInt] struct {
+	// Parameters is the parameters of depth zero, which the hierarchy is derived from.
+	Parameters Parameters[T]
+	// Value has length HierarchyDepth.
+	// Value[d] is the EvaluationKey for parameters Parameters.AtDepth(d+1).
+	Value []EvaluationKey[T]
+}
+
+// NewHierarchicalEvaluationKey creates a new HierarchicalEvaluationKey.
+func NewHierarchicalEvaluationKey[T TorusInt](params Parameters[T]) HierarchicalEvaluationKey[T] {
+	evks := make([]EvaluationKey[T], params.hierarchyDepth)
+	for i := range evks {
+		evks[i] = NewEvaluationKey(params.AtDepth(i + 1))
+	}
+	return HierarchicalEvaluationKey[T]{Parameters: params, Value: evks}
+}
+
+// Depth returns the number of depths of this key.
+func (evk HierarchicalEvaluationKey[T]) Depth() int {
+	return len(evk.Value)
+}
+
+// Copy returns a copy of the key.
+func (evk HierarchicalEvaluationKey[T]) Copy() HierarchicalEvaluationKey[T] {
+	evksCopy := make([]EvaluationKey[T], len(evk.Value))
+	for i := range evk.Value {
+		evksCopy[i] = evk.Value[i].Copy()
+	}
+	return HierarchicalEvaluationKey[T]{Parameters: evk.Parameters, Value: evksCopy}
+}
+
+// CopyFrom copies values from key.
+func (evk *HierarchicalEvaluationKey[T]) CopyFrom(evkIn HierarchicalEvaluationKey[T]) {
+	for i := range evk.Value {
+		evk.Value[i].CopyFrom(evkIn.Value[i])
+	}
+	evk.Parameters = evkIn.Parameters
+}
+
+// Clear clears the key.
+func (evk *HierarchicalEvaluationKey[T]) Clear() {
+	for i := range evk.Value {
+		evk.Value[i].Clear()
+	}
 }
 
 // BlindRotateKey is a key for blind rotation.
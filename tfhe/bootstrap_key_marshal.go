@@ -3,14 +3,38 @@ package tfhe
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
 )
 
+const (
+	// keySwitchKeyAbsent indicates that no keyswitch key is written.
+	keySwitchKeyAbsent byte = iota
+	// keySwitchKeyLWE indicates that the LWEKeySwitchKey is written.
+	keySwitchKeyLWE
+	// keySwitchKeyGLWE indicates that the GLWEKeySwitchKey is written.
+	keySwitchKeyGLWE
+)
+
+// keySwitchKeyType returns the type of the keyswitch key present in evk.
+func (evk EvaluationKey[T]) keySwitchKeyType() byte {
+	switch {
+	case len(evk.GLWEKeySwitchKey.Value) > 0:
+		return keySwitchKeyGLWE
+	case len(evk.KeySwitchKey.Value) > 0:
+		return keySwitchKeyLWE
+	}
+	return keySwitchKeyAbsent
+}
+
 // ByteSize returns the size of the key in bytes.
 func (evk EvaluationKey[T]) ByteSize() int {
-	if len(evk.KeySwitchKey.Value) > 0 {
+	switch evk.keySwitchKeyType() {
+	case keySwitchKeyGLWE:
+		return 1 + evk.BlindRotateKey.ByteSize() + evk.GLWEKeySwitchKey.ByteSize()
+	case keySwitchKeyLWE:
 		return 1 + evk.BlindRotateKey.ByteSize() + evk.KeySwitchKey.ByteSize()
-	} else {
+	default:
 		return 1 + evk.BlindRotateKey.ByteSize() + evk.KeySwitchKey.GadgetParameters.ByteSize()
 	}
 }
@@ -19,21 +43,19 @@ func (evk EvaluationKey[T]) ByteSize() int {
 //
 // The encoded form is as follows:
 //
-//	 [1] IsKeySwitchKeyPresent
+//	 [1] KeySwitchKeyType
 //		 BlindRotateKey
 //		 KeySwitchKey
 //
-// If IsKeySwitchKeyPresent is 0, then only the GadgetParameters of the KeySwitchKey is written.
+// If KeySwitchKeyType is 0, then only the GadgetParameters of the KeySwitchKey is written.
+// If KeySwitchKeyType is 1, then the KeySwitchKey is written,
+// and if KeySwitchKeyType is 2, then the GLWEKeySwitchKey is written.
 func (evk EvaluationKey[T]) WriteTo(w io.Writer) (n int64, err error) {
 	var nWrite int
 	var nWrite64 int64
 
-	var isKeySwitchKeyPresent byte
-	if len(evk.KeySwitchKey.Value) > 0 {
-		isKeySwitchKeyPresent = 1
-	}
-
-	if nWrite, err = w.Write([]byte{isKeySwitchKeyPresent}); err != nil {
+	keySwitchKeyType := evk.keySwitchKeyType()
+	if nWrite, err = w.Write([]byte{keySwitchKeyType}); err != nil {
 		return n + int64(nWrite), err
 	}
 	n += int64(nWrite)
@@ -43,16 +65,22 @@ func (evk EvaluationKey[T]) WriteTo(w io.Writer) (n int64, err error) {
 	}
 	n += nWrite64
 
-	if isKeySwitchKeyPresent == 0 {
-		if nWrite64, err = evk.KeySwitchKey.GadgetParameters.WriteTo(w); err != nil {
+	switch keySwitchKeyType {
+	case keySwitchKeyGLWE:
+		if nWrite64, err = evk.GLWEKeySwitchKey.WriteTo(w); err != nil {
 			return n + nWrite64, err
 		}
 		n += nWrite64
-	} else {
+	case keySwitchKeyLWE:
 		if nWrite64, err = evk.KeySwitchKey.WriteTo(w); err != nil {
 			return n + nWrite64, err
 		}
 		n += nWrite64
+	default:
+		if nWrite64, err = evk.KeySwitchKey.GadgetParameters.WriteTo(w); err != nil {
+			return n + nWrite64, err
+		}
+		n += nWrite64
 	}
 
 	if n < int64(evk.ByteSize()) {
@@ -72,14 +100,15 @@ func (evk *EvaluationKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		return n + int64(nRead), err
 	}
 	n += int64(nRead)
-	isKeySwitchKeyPresent := buf[0]
+	keySwitchKeyType := buf[0]
 
 	if nRead64, err = evk.BlindRotateKey.ReadFrom(r); err != nil {
 		return n + nRead64, err
 	}
 	n += nRead64
 
-	if isKeySwitchKeyPresent == 0 {
+	switch keySwitchKeyType {
+	case keySwitchKeyAbsent:
 		var keySwitchParams GadgetParameters[T]
 		if nRead64, err = keySwitchParams.ReadFrom(r); err != nil {
 			return n + nRead64, err
@@ -87,11 +116,23 @@ func (evk *EvaluationKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		n += nRead64
 
 		evk.KeySwitchKey = NewLWEKeySwitchKeyCustom(0, 0, keySwitchParams)
-	} else {
+		evk.GLWEKeySwitchKey = GLWEKeySwitchKey[T]{}
+	case keySwitchKeyLWE:
 		if nRead64, err = evk.KeySwitchKey.ReadFrom(r); err != nil {
 			return n + nRead64, err
 		}
 		n += nRead64
+
+		evk.GLWEKeySwitchKey = GLWEKeySwitchKey[T]{}
+	case keySwitchKeyGLWE:
+		if nRead64, err = evk.GLWEKeySwitchKey.ReadFrom(r); err != nil {
+			return n + nRead64, err
+		}
+		n += nRead64
+
+		evk.KeySwitchKey = NewLWEKeySwitchKeyCustom(0, 0, evk.GLWEKeySwitchKey.GadgetParameters)
+	default:
+		return n, errors.New("KeySwitchKeyType not valid")
 	}
 
 	return
@@ -111,6 +152,147 @@ func (evk *EvaluationKey[T]) UnmarshalBinary(data []byte) error {
 	return err
 }
 
+// ByteSize returns the size of the key in bytes.
+func (evk HierarchicalEvaluationKey[T]) ByteSize() int {
+	size := evk.Parameters.ByteSize() + 8
+	for i := range evk.Value {
+		size += evk.Value[i].ByteSize()
+	}
+	return size
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	    Parameters
+//	[8] Depth
+//	    Value
+func (evk HierarchicalEvaluationKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	if nWrite64, err = evk.Parameters.WriteTo(w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	depth := len(evk.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(depth))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	for i := range evk.Value {
+		if nWrite64, err = evk.Value[i].WriteTo(w); err != nil {
+			return n + nWrite64, err
+		}
+		n += nWrite64
+	}
+
+	if n < int64(evk.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// checkEvaluationKeyShape returns an error if evk does not have the shape
+// of the key created by [NewEvaluationKey] with params.
+func checkEvaluationKeyShape[T TorusInt](evk EvaluationKey[T], params Parameters[T]) error {
+	brk := evk.BlindRotateKey
+	switch {
+	case brk.GadgetParameters != params.blindRotateParameters, len(brk.Value) != params.lweDimension:
+		return errors.New("BlindRotateKey shape mismatch")
+	case len(brk.Value[0].Value) != params.glweRank+1, brk.Value[0].Value[0].Value[0].Value[0].Degree() != params.polyDegree:
+		return errors.New("BlindRotateKey shape mismatch")
+	}
+
+	ksk := evk.KeySwitchKey
+	glweKsk := evk.GLWEKeySwitchKey
+	if params.keySwitchMethod == KeySwitchMethodGLWE {
+		// This is the input GLWERank of NewGLWEKeySwitchKeyForBootstrap.
+		inputGLWERank := (params.glweDimension - params.lweDimension + params.polyDegree - 1) / params.polyDegree
+		switch {
+		case len(ksk.Value) != 0:
+			return errors.New("KeySwitchKey shape mismatch")
+		case glweKsk.GadgetParameters != params.keySwitchParameters, glweKsk.InputGLWERank() != inputGLWERank:
+			return errors.New("GLWEKeySwitchKey shape mismatch")
+		case inputGLWERank > 0 && (len(glweKsk.Value[0].Value[0].Value) != params.glweRank+1 || glweKsk.Value[0].Value[0].Value[0].Degree() != params.polyDegree):
+			return errors.New("GLWEKeySwitchKey shape mismatch")
+		}
+		return nil
+	}
+
+	switch {
+	case len(glweKsk.Value) != 0:
+		return errors.New("GLWEKeySwitchKey shape mismatch")
+	case ksk.GadgetParameters != params.keySwitchParameters, ksk.InputLWEDimension() != params.glweDimension-params.lweDimension:
+		return errors.New("KeySwitchKey shape mismatch")
+	case len(ksk.Value) > 0 && len(ksk.Value[0].Value[0].Value) != params.lweDimension+1:
+		return errors.New("KeySwitchKey shape mismatch")
+	}
+	return nil
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+//
+// Returns an error when Depth is not equal to HierarchyDepth of the encoded Parameters,
+// or when the key of depth d does not have the shape of Parameters.AtDepth(d+1).
+func (evk *HierarchicalEvaluationKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	var params Parameters[T]
+	if nRead64, err = params.ReadFrom(r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	depth := int(binary.BigEndian.Uint64(buf[:]))
+	if depth != params.hierarchyDepth {
+		return n, errors.New("Depth not equal to HierarchyDepth")
+	}
+
+	evks := make([]EvaluationKey[T], depth)
+	for i := range evks {
+		if nRead64, err = evks[i].ReadFrom(r); err != nil {
+			return n + nRead64, err
+		}
+		n += nRead64
+
+		if err = checkEvaluationKeyShape(evks[i], params.AtDepth(i+1)); err != nil {
+			return n, err
+		}
+	}
+
+	evk.Parameters = params
+	evk.Value = evks
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (evk HierarchicalEvaluationKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, evk.ByteSize()))
+	_, err = evk.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (evk *HierarchicalEvaluationKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := evk.ReadFrom(buf)
+	return err
+}
+
 // ByteSize returns the size of the key in bytes.
 func (brk BlindRotateKey[T]) ByteSize() int {
 	lweDimension := len(brk.Value)
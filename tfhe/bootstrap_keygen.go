@@ -13,6 +13,14 @@ import (
 // This can take a long time.
 // Use [*Encryptor.GenEvaluationKeyParallel] for better key generation performance.
 func (e *Encryptor[T]) GenEvaluationKey() EvaluationKey[T] {
+	if e.Parameters.keySwitchMethod == KeySwitchMethodGLWE {
+		return EvaluationKey[T]{
+			BlindRotateKey:   e.GenBlindRotateKey(),
+			KeySwitchKey:     NewLWEKeySwitchKeyCustom(0, 0, e.Parameters.keySwitchParameters),
+			GLWEKeySwitchKey: e.GenGLWEKeySwitchKeyForBootstrap(),
+		}
+	}
+
 	return EvaluationKey[T]{
 		BlindRotateKey: e.GenBlindRotateKey(),
 		KeySwitchKey:   e.GenKeySwitchKeyForBootstrap(),
@@ -21,12 +29,51 @@ func (e *Encryptor[T]) GenEvaluationKey() EvaluationKey[T] {
 
 // GenEvaluationKeyParallel samples a new evaluation key for bootstrapping in parallel.
 func (e *Encryptor[T]) GenEvaluationKeyParallel() EvaluationKey[T] {
+	if e.Parameters.keySwitchMethod == KeySwitchMethodGLWE {
+		return EvaluationKey[T]{
+			BlindRotateKey:   e.GenBlindRotateKeyParallel(),
+			KeySwitchKey:     NewLWEKeySwitchKeyCustom(0, 0, e.Parameters.keySwitchParameters),
+			GLWEKeySwitchKey: e.GenGLWEKeySwitchKeyForBootstrapParallel(),
+		}
+	}
+
 	return EvaluationKey[T]{
 		BlindRotateKey: e.GenBlindRotateKeyParallel(),
 		KeySwitchKey:   e.GenKeySwitchKeyForBootstrapParallel(),
 	}
 }
 
+// GenHierarchicalEvaluationKey samples a new evaluation key for the evaluator hierarchy.
+// encryptors should be created by [NewEncryptorHierarchyWithSharedLWEKey] with params.
+//
+// This can take a long time.
+// Use [GenHierarchicalEvaluationKeyParallel] for better key generation performance.
+func GenHierarchicalEvaluationKey[T TorusInt](params Parameters[T], encryptors []*Encryptor[T]) HierarchicalEvaluationKey[T] {
+	if len(encryptors) != params.hierarchyDepth {
+		panic("Encryptors length not equal to HierarchyDepth")
+	}
+
+	evks := make([]EvaluationKey[T], len(encryptors))
+	for i := range encryptors {
+		evks[i] = encryptors[i].GenEvaluationKey()
+	}
+	return HierarchicalEvaluationKey[T]{Parameters: params, Value: evks}
+}
+
+// GenHierarchicalEvaluationKeyParallel samples a new evaluation key for the evaluator hierarchy in parallel.
+// encryptors should be created by [NewEncryptorHierarchyWithSharedLWEKey] with params.
+func GenHierarchicalEvaluationKeyParallel[T TorusInt](params Parameters[T], encryptors []*Encryptor[T]) HierarchicalEvaluationKey[T] {
+	if len(encryptors) != params.hierarchyDepth {
+		panic("Encryptors length not equal to HierarchyDepth")
+	}
+
+	evks := make([]EvaluationKey[T], len(encryptors))
+	for i := range encryptors {
+		evks[i] = encryptors[i].GenEvaluationKeyParallel()
+	}
+	return HierarchicalEvaluationKey[T]{Parameters: params, Value: evks}
+}
+
 // GenBlindRotateKey samples a new bootstrapping key.
 //
 // This can take a long time.
@@ -167,3 +214,97 @@ func (e *Encryptor[T]) GenKeySwitchKeyForBootstrapParallel() LWEKeySwitchKey[T]
 
 	return ksk
 }
+
+// GenGLWEKeySwitchKeyForBootstrap samples a new keyswitch key LWELargeKey -> LWEKey
+// packed to polynomials, used for bootstrapping with KeySwitchMethodGLWE.
+//
+// The i-th GLev ciphertext encrypts the polynomial whose coefficients are
+// LWELargeKey[LWEDimension + i*PolyDegree:LWEDimension + (i+1)*PolyDegree],
+// under the GLWE key whose coefficients are LWEKey padded with zeros.
+// Like [*Encryptor.GenKeySwitchKeyForBootstrap], this is encrypted with LWEStdDev.
+func (e *Encryptor[T]) GenGLWEKeySwitchKeyForBootstrap() GLWEKeySwitchKey[T] {
+	ksk := NewGLWEKeySwitchKeyForBootstrap(e.Parameters)
+	skOut := e.genPackedLWEKey()
+
+	for i := 0; i < ksk.InputGLWERank(); i++ {
+		for j := 0; j < e.Parameters.keySwitchParameters.level; j++ {
+			e.encryptGLWEKeySwitchKeyForBootstrapAssign(skOut, i, j, ksk.Value[i].Value[j])
+		}
+	}
+
+	return ksk
+}
+
+// GenGLWEKeySwitchKeyForBootstrapParallel samples a new keyswitch key LWELargeKey -> LWEKey
+// packed to polynomials in parallel, used for bootstrapping with KeySwitchMethodGLWE.
+func (e *Encryptor[T]) GenGLWEKeySwitchKeyForBootstrapParallel() GLWEKeySwitchKey[T] {
+	ksk := NewGLWEKeySwitchKeyForBootstrap(e.Parameters)
+	skOut := e.genPackedLWEKey()
+
+	workSize := ksk.InputGLWERank() * e.Parameters.keySwitchParameters.level
+	chunkCount := num.Min(runtime.NumCPU(), workSize)
+
+	encryptorPool := make([]*Encryptor[T], chunkCount)
+	for i := range encryptorPool {
+		encryptorPool[i] = e.ShallowCopy()
+	}
+
+	jobs := make(chan [2]int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < ksk.InputGLWERank(); i++ {
+			for j := 0; j < e.Parameters.keySwitchParameters.level; j++ {
+				jobs <- [2]int{i, j}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(chunkCount)
+	for i := 0; i < chunkCount; i++ {
+		go func(i int) {
+			eIdx := encryptorPool[i]
+			for job := range jobs {
+				i, j := job[0], job[1]
+				eIdx.encryptGLWEKeySwitchKeyForBootstrapAssign(skOut, i, j, ksk.Value[i].Value[j])
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	return ksk
+}
+
+// genPackedLWEKey returns LWEKey packed to GLWERank polynomials and padded with zeros,
+// in the fourier domain.
+func (e *Encryptor[T]) genPackedLWEKey() FourierGLWESecretKey[T] {
+	skOut := NewFourierGLWESecretKey(e.Parameters)
+	for i := 0; i < e.Parameters.glweRank; i++ {
+		e.buffer.ptGGSW.Clear()
+		start := num.Min(i*e.Parameters.polyDegree, e.Parameters.lweDimension)
+		end := num.Min((i+1)*e.Parameters.polyDegree, e.Parameters.lweDimension)
+		vec.CopyAssign(e.SecretKey.LWEKey.Value[start:end], e.buffer.ptGGSW.Coeffs)
+		e.PolyEvaluator.ToFourierPolyAssign(e.buffer.ptGGSW, skOut.Value[i])
+	}
+	return skOut
+}
+
+// encryptGLWEKeySwitchKeyForBootstrapAssign encrypts the i-th polynomial of the tail of LWELargeKey
+// scaled by the j-th gadget value of KeySwitchParameters under skOut, and writes it to ctOut.
+func (e *Encryptor[T]) encryptGLWEKeySwitchKeyForBootstrapAssign(skOut FourierGLWESecretKey[T], i, j int, ctOut FourierGLWECiphertext[T]) {
+	start := e.Parameters.lweDimension + i*e.Parameters.polyDegree
+	end := num.Min(start+e.Parameters.polyDegree, e.Parameters.glweDimension)
+
+	e.buffer.ptGGSW.Clear()
+	vec.CopyAssign(e.SecretKey.LWELargeKey.Value[start:end], e.buffer.ptGGSW.Coeffs)
+	e.PolyEvaluator.ScalarMulPolyAssign(e.buffer.ptGGSW, e.Parameters.keySwitchParameters.BaseQ(j), e.buffer.ctGLWE.Value[0])
+
+	for k := 0; k < e.Parameters.glweRank; k++ {
+		e.UniformSampler.SamplePolyAssign(e.buffer.ctGLWE.Value[k+1])
+		e.PolyEvaluator.ShortFourierPolyMulSubPolyAssign(e.buffer.ctGLWE.Value[k+1], skOut.Value[k], e.buffer.ctGLWE.Value[0])
+	}
+	e.GaussianSampler.SamplePolyAddAssign(e.Parameters.LWEStdDevQ(), e.buffer.ctGLWE.Value[0])
+
+	e.ToFourierGLWECiphertextAssign(e.buffer.ctGLWE, ctOut)
+}
@@ -1,9 +1,7 @@
 package tfhe
 
 import (
-	"github.com/sp301415/tfhe-go/math/num"
 	"github.com/sp301415/tfhe-go/math/poly"
-	"github.com/sp301415/tfhe-go/math/vec"
 )
 
 // LookUpTable is a polynomial that is the lookup table
@@ -11,6 +9,10 @@ import (
 type LookUpTable[T TorusInt] struct {
 	// Value has length polyExtendFactor.
 	Value []poly.Poly[T]
+
+	// Parameters is the parameters this LUT was created for.
+	// This is zero if the LUT was created by [NewLookUpTableCustom].
+	Parameters Parameters[T]
 }
 
 // NewLookUpTable creates a new lookup table.
@@ -20,7 +22,7 @@ func NewLookUpTable[T TorusInt](params Parameters[T]) LookUpTable[T] {
 		lut[i] = poly.NewPoly[T](params.polyDegree)
 	}
 
-	return LookUpTable[T]{Value: lut}
+	return LookUpTable[T]{Value: lut, Parameters: params}
 }
 
 // NewLookUpTableCustom creates a new lookup table with custom size.
@@ -39,7 +41,7 @@ func (lut LookUpTable[T]) Copy() LookUpTable[T] {
 	for i := 0; i < len(lut.Value); i++ {
 		lutCopy[i] = lut.Value[i].Copy()
 	}
-	return LookUpTable[T]{Value: lutCopy}
+	return LookUpTable[T]{Value: lutCopy, Parameters: lut.Parameters}
 }
 
 // CopyFrom copies values from the LUT.
@@ -109,24 +111,14 @@ func (e *Evaluator[T]) GenLookUpTableCustomFull(f func(int) T, messageModulus T)
 // GenLookUpTableCustomFullAssign generates a lookup table based on function f using custom messageModulus and scale and writes it to lutOut.
 // Output of f is encoded as-is.
 func (e *Evaluator[T]) GenLookUpTableCustomFullAssign(f func(int) T, messageModulus T, lutOut LookUpTable[T]) {
-	for x := 0; x < int(messageModulus); x++ {
-		start := num.DivRound(x*e.Parameters.lookUpTableSize, int(messageModulus))
-		end := num.DivRound((x+1)*e.Parameters.lookUpTableSize, int(messageModulus))
-		y := f(x)
-		for xx := start; xx < end; xx++ {
-			e.buffer.lutRaw[xx] = y
-		}
-	}
-
-	offset := num.DivRound(e.Parameters.lookUpTableSize, int(2*messageModulus))
-	vec.RotateInPlace(e.buffer.lutRaw, -offset)
-	for i := e.Parameters.lookUpTableSize - offset; i < e.Parameters.lookUpTableSize; i++ {
-		e.buffer.lutRaw[i] = -e.buffer.lutRaw[i]
-	}
+	e.lookUpTableBuilder(e.Parameters.polyExtendFactor, e.Parameters.polyDegree, NegacyclicPadding, OffsetCellStart).BuildAssign(f, messageModulus, lutOut)
+}
 
-	for i := 0; i < e.Parameters.polyExtendFactor; i++ {
-		for j := 0; j < e.Parameters.polyDegree; j++ {
-			lutOut.Value[i].Coeffs[j] = e.buffer.lutRaw[j*e.Parameters.polyExtendFactor+i]
-		}
-	}
+// lookUpTableBuilder returns the LookUpTableBuilder of this Evaluator with given configuration.
+func (e *Evaluator[T]) lookUpTableBuilder(extendFactor, polyDegree int, mode NegacyclicMode, offset LookUpTableOffset) *LookUpTableBuilder[T] {
+	e.buffer.lutBuilder.ExtendFactor = extendFactor
+	e.buffer.lutBuilder.PolyDegree = polyDegree
+	e.buffer.lutBuilder.Mode = mode
+	e.buffer.lutBuilder.Offset = offset
+	return e.buffer.lutBuilder
 }
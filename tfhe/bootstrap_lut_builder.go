@@ -0,0 +1,173 @@
+package tfhe
+
+import (
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/math/vec"
+)
+
+// NegacyclicMode determines how a LookUpTable deals with
+// the negacyclicity of blind rotation.
+type NegacyclicMode int
+
+const (
+	// NegacyclicPadding spreads the cells over the whole LUT,
+	// so that the LUT covers only half of the torus.
+	// The input must have a padding bit,
+	// since inputs in the other half evaluate to the negated output.
+	NegacyclicPadding NegacyclicMode = iota
+	// NegacyclicMirror fills the upper half of the cells with
+	// the values of the upper half of the function, negated and in reverse order.
+	// That is, the cell of input x >= MessageModulus / 2 holds
+	// -f(MessageModulus - x + MessageModulus/2 - 1).
+	// This is used by full-domain bootstrapping.
+	NegacyclicMirror
+)
+
+// LookUpTableOffset determines how a LookUpTable is rotated,
+// depending on where the inputs fall in each cell.
+type LookUpTableOffset int
+
+const (
+	// OffsetCellStart assumes that the inputs are at the start of each cell,
+	// as in fresh encryptions.
+	// The LUT is rotated so that the first slot of each cell is centered at the input.
+	OffsetCellStart LookUpTableOffset = iota
+	// OffsetCellCenter assumes that the inputs are at the center of each cell,
+	// as in the outputs of the compress bootstrapping of full-domain bootstrapping.
+	// The LUT is rotated so that the first slot of each cell is centered at the input.
+	// If there is only one slot, the LUT is not rotated.
+	OffsetCellCenter
+)
+
+// LookUpTableBuilder builds lookup tables of arbitrary functions.
+//
+// The LUT is built from a raw table of size ExtendFactor * PolyDegree,
+// which is split into one cell for each input, and each cell into one slot for each function.
+// The raw table is then negacyclically rotated according to Offset,
+// and interleaved into ExtendFactor polynomials.
+//
+// LookUpTableBuilder is not safe for concurrent use.
+type LookUpTableBuilder[T TorusInt] struct {
+	// ExtendFactor is the number of polynomials of the LUT.
+	ExtendFactor int
+	// PolyDegree is the degree of the polynomials of the LUT.
+	// If the output LUT has larger polynomials, only the first PolyDegree coefficients are written.
+	PolyDegree int
+
+	// Mode is the negacyclic mode of the LUT.
+	Mode NegacyclicMode
+	// Offset is the offset policy of the LUT.
+	Offset LookUpTableOffset
+
+	// lutRaw is the raw table.
+	// It is reallocated if it is smaller than ExtendFactor * PolyDegree.
+	lutRaw []T
+}
+
+// NewLookUpTableBuilder creates a new LookUpTableBuilder.
+func NewLookUpTableBuilder[T TorusInt](extendFactor, polyDegree int, mode NegacyclicMode, offset LookUpTableOffset) *LookUpTableBuilder[T] {
+	return &LookUpTableBuilder[T]{
+		ExtendFactor: extendFactor,
+		PolyDegree:   polyDegree,
+
+		Mode:   mode,
+		Offset: offset,
+
+		lutRaw: make([]T, extendFactor*polyDegree),
+	}
+}
+
+// Size returns the size of the raw table, ExtendFactor * PolyDegree.
+func (b *LookUpTableBuilder[T]) Size() int {
+	return b.ExtendFactor * b.PolyDegree
+}
+
+// Build builds a lookup table based on function f.
+// Input of f is cut by messageModulus, and output of f is encoded as-is.
+func (b *LookUpTableBuilder[T]) Build(f func(int) T, messageModulus T) LookUpTable[T] {
+	lutOut := NewLookUpTableCustom[T](b.ExtendFactor, b.PolyDegree)
+	b.BuildAssign(f, messageModulus, lutOut)
+	return lutOut
+}
+
+// BuildAssign builds a lookup table based on function f and writes it to lutOut.
+// Input of f is cut by messageModulus, and output of f is encoded as-is.
+func (b *LookUpTableBuilder[T]) BuildAssign(f func(int) T, messageModulus T, lutOut LookUpTable[T]) {
+	b.BuildMultiValueAssign([]func(int) T{f}, messageModulus, lutOut)
+}
+
+// BuildMultiValue builds a lookup table packing functions fs.
+// Input of each function is cut by messageModulus, and output is encoded as-is.
+//
+// Panics when len(fs) is smaller than one,
+// or when the number of slots is larger than Size / messageModulus.
+func (b *LookUpTableBuilder[T]) BuildMultiValue(fs []func(int) T, messageModulus T) LookUpTable[T] {
+	lutOut := NewLookUpTableCustom[T](b.ExtendFactor, b.PolyDegree)
+	b.BuildMultiValueAssign(fs, messageModulus, lutOut)
+	return lutOut
+}
+
+// BuildMultiValueAssign builds a lookup table packing functions fs and writes it to lutOut.
+// Input of each function is cut by messageModulus, and output is encoded as-is.
+//
+// Each cell is split into slots, one for each function.
+// The number of slots is the smallest power of two not smaller than len(fs),
+// and the unused slots are filled with zero.
+//
+// Panics when len(fs) is smaller than one,
+// or when the number of slots is larger than Size / messageModulus.
+func (b *LookUpTableBuilder[T]) BuildMultiValueAssign(fs []func(int) T, messageModulus T, lutOut LookUpTable[T]) {
+	size := b.Size()
+	cellCount := int(messageModulus)
+	slotCount := multiValueSlotCount(len(fs))
+	switch {
+	case len(fs) < 1:
+		panic("Number of functions smaller than one")
+	case slotCount > size/cellCount:
+		panic("Number of functions larger than Size / MessageModulus")
+	}
+
+	if len(b.lutRaw) < size {
+		b.lutRaw = make([]T, size)
+	}
+	lutRaw := b.lutRaw[:size]
+	vec.Fill(lutRaw, 0)
+
+	for x := 0; x < cellCount; x++ {
+		start := num.DivRound(x*size, cellCount)
+		end := num.DivRound((x+1)*size, cellCount)
+		for v, f := range fs {
+			var y T
+			if b.Mode == NegacyclicMirror && x >= cellCount/2 {
+				y = -f(cellCount - x + cellCount/2 - 1)
+			} else {
+				y = f(x)
+			}
+
+			slotStart := start + num.DivRound(v*(end-start), slotCount)
+			slotEnd := start + num.DivRound((v+1)*(end-start), slotCount)
+			vec.Fill(lutRaw[slotStart:slotEnd], y)
+		}
+	}
+
+	switch b.Offset {
+	case OffsetCellStart:
+		offset := num.DivRound(size, 2*cellCount*slotCount)
+		vec.RotateInPlace(lutRaw, -offset)
+		for i := size - offset; i < size; i++ {
+			lutRaw[i] = -lutRaw[i]
+		}
+	case OffsetCellCenter:
+		offset := num.DivRound(size, 2*cellCount) - num.DivRound(size, 2*cellCount*slotCount)
+		vec.RotateInPlace(lutRaw, offset)
+		for i := 0; i < offset; i++ {
+			lutRaw[i] = -lutRaw[i]
+		}
+	}
+
+	for i := 0; i < b.ExtendFactor; i++ {
+		for j := 0; j < b.PolyDegree; j++ {
+			lutOut.Value[i].Coeffs[j] = lutRaw[j*b.ExtendFactor+i]
+		}
+	}
+}
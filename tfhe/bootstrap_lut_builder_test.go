@@ -0,0 +1,263 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/math/vec"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+// The reference generators below are the inline loops that were used
+// before LookUpTableBuilder, kept for equivalence tests.
+
+// referenceCells fills size entries with cells of width size / messageModulus.
+// If mirror is true, the upper half holds the upper half of f negated and in reverse order.
+func referenceCells[T tfhe.TorusInt](f func(int) T, messageModulus, size int, mirror bool) []T {
+	lutRaw := make([]T, size)
+	for x := 0; x < messageModulus; x++ {
+		start := num.DivRound(x*size, messageModulus)
+		end := num.DivRound((x+1)*size, messageModulus)
+		y := f(x)
+		if mirror && x >= messageModulus/2 {
+			y = -f(messageModulus - x + messageModulus/2 - 1)
+		}
+		for xx := start; xx < end; xx++ {
+			lutRaw[xx] = y
+		}
+	}
+	return lutRaw
+}
+
+// referenceRotate negacyclically rotates lutRaw to the left by offset.
+// If offset is negative, it is rotated to the right.
+func referenceRotate[T tfhe.TorusInt](lutRaw []T, offset int) {
+	vec.RotateInPlace(lutRaw, -offset)
+	if offset < 0 {
+		for i := 0; i < -offset; i++ {
+			lutRaw[i] = -lutRaw[i]
+		}
+		return
+	}
+	for i := len(lutRaw) - offset; i < len(lutRaw); i++ {
+		lutRaw[i] = -lutRaw[i]
+	}
+}
+
+// referenceInterleave writes lutRaw to lutOut, interleaved into extendFactor polynomials.
+func referenceInterleave[T tfhe.TorusInt](lutRaw []T, extendFactor, polyDegree int) tfhe.LookUpTable[T] {
+	lutOut := tfhe.NewLookUpTableCustom[T](extendFactor, polyDegree)
+	for i := 0; i < extendFactor; i++ {
+		for j := 0; j < polyDegree; j++ {
+			lutOut.Value[i].Coeffs[j] = lutRaw[j*extendFactor+i]
+		}
+	}
+	return lutOut
+}
+
+func referenceLookUpTable[T tfhe.TorusInt](params tfhe.Parameters[T], f func(int) T, messageModulus T) tfhe.LookUpTable[T] {
+	lutRaw := referenceCells(f, int(messageModulus), params.LookUpTableSize(), false)
+	referenceRotate(lutRaw, num.DivRound(params.LookUpTableSize(), int(2*messageModulus)))
+	lut := referenceInterleave(lutRaw, params.PolyExtendFactor(), params.PolyDegree())
+	lut.Parameters = params
+	return lut
+}
+
+func referenceCompressLUT[T tfhe.TorusInt](params tfhe.Parameters[T], messageModulus T, size, extendFactor, polyDegree int) tfhe.LookUpTable[T] {
+	halfQ := T(1) << (params.LogQ() - 1)
+	lutRaw := make([]T, size)
+	for x := T(0); x < messageModulus/2; x++ {
+		start := num.DivRound(2*int(x)*size, int(messageModulus))
+		end := num.DivRound(2*(int(x)+1)*size, int(messageModulus))
+		for xx := start; xx < end; xx++ {
+			lutRaw[xx] = (halfQ/messageModulus)*x + (halfQ / 2 / messageModulus)
+		}
+	}
+	referenceRotate(lutRaw, num.DivRound(size, int(messageModulus)))
+	return referenceInterleave(lutRaw, extendFactor, polyDegree)
+}
+
+func referenceDecomposedLUT[T tfhe.TorusInt](params tfhe.Parameters[T], fs []func(int) T, messageModulus T, extended bool) tfhe.DecomposedLookUpTable[T] {
+	lut := tfhe.NewDecomposedLookUpTable(params)
+	if extended {
+		lut = tfhe.NewDecomposedLookUpTableEBS(params)
+	}
+	lut.MessageModulus = messageModulus
+	lut.ValueCount = len(fs)
+
+	slotCount := 1
+	for slotCount < len(fs) {
+		slotCount <<= 1
+	}
+	lookUpTableSize := params.LookUpTableSize()
+	polyDegree := params.BasePolyDegree()
+	logExtendFactor := num.Log2(params.BaseExtendFactor())
+
+	negFuncEval := make([][][]T, logExtendFactor)
+	baseFuncEval := make([][]T, len(fs))
+	for v, f := range fs {
+		funcEval := make([]T, messageModulus)
+		for x := range funcEval {
+			funcEval[x] = f(x)
+		}
+		for i := 0; i < logExtendFactor; i++ {
+			n := len(funcEval) / 2
+			newFuncEval := make([]T, n)
+			negFuncEval[i] = append(negFuncEval[i], make([]T, n))
+			for j := 0; j < n; j++ {
+				newFuncEval[j] = funcEval[j]/2 + funcEval[j+n]/2
+				negFuncEval[i][v][j] = funcEval[j]/2 - funcEval[j+n]/2
+			}
+			funcEval = newFuncEval
+		}
+		baseFuncEval[v] = funcEval
+	}
+
+	for k := 0; k < logExtendFactor; k++ {
+		length := lookUpTableSize / (1 << (k + 1))
+		lutRaw := make([]T, length)
+		for x := 0; x < int(messageModulus)/(1<<(k+1)); x++ {
+			start := num.DivRound(x*lookUpTableSize, int(messageModulus))
+			end := num.DivRound((x+1)*lookUpTableSize, int(messageModulus))
+			for v := range fs {
+				slotStart := start + num.DivRound(v*(end-start), slotCount)
+				slotEnd := start + num.DivRound((v+1)*(end-start), slotCount)
+				for xx := slotStart; xx < slotEnd; xx++ {
+					lutRaw[xx] = negFuncEval[k][v][x]
+				}
+			}
+		}
+		referenceRotate(lutRaw, num.DivRound(lookUpTableSize, int(2*messageModulus)*slotCount))
+		if extended {
+			lut.NegLUTs[k] = referenceInterleave(lutRaw, length/polyDegree, polyDegree)
+		} else {
+			lut.NegLUTs[k] = referenceInterleave(lutRaw, 1, length)
+		}
+	}
+
+	baseMessageModulus := messageModulus / T(params.BaseExtendFactor())
+	lutRaw := make([]T, polyDegree)
+	for x := T(0); x < baseMessageModulus; x++ {
+		start := num.DivRound(int(x)*polyDegree, int(baseMessageModulus))
+		end := num.DivRound((int(x)+1)*polyDegree, int(baseMessageModulus))
+		for v := range fs {
+			slotStart := start + num.DivRound(v*(end-start), slotCount)
+			slotEnd := start + num.DivRound((v+1)*(end-start), slotCount)
+			y := baseFuncEval[v][int(x)]
+			if x >= baseMessageModulus/2 {
+				y = -baseFuncEval[v][int(baseMessageModulus-x+baseMessageModulus/2-1)]
+			}
+			for xx := slotStart; xx < slotEnd; xx++ {
+				lutRaw[xx] = y
+			}
+		}
+	}
+	referenceRotate(lutRaw, -(num.DivRound(polyDegree, int(2*baseMessageModulus)) - num.DivRound(polyDegree, int(2*baseMessageModulus)*slotCount)))
+	lut.BaseLUT = referenceInterleave(lutRaw, 1, polyDegree)
+
+	return lut
+}
+
+func testLookUpTableBuilderEquivalence[T tfhe.TorusInt](t *testing.T, params tfhe.Parameters[T]) {
+	eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[T]{})
+	fs := []func(int) T{
+		func(x int) T { return T(3*x*x+1) * params.Scale() },
+		func(x int) T { return T(x*x*12345678+99) << 7 },
+		func(x int) T { return T(x / 2) },
+	}
+
+	t.Run("GenLookUpTable", func(t *testing.T) {
+		assert.Equal(t, referenceLookUpTable(params, fs[0], params.MessageModulus()), eval.GenLookUpTableFull(fs[0]))
+		for _, messageModulus := range []T{3, 5, 6, 7, 10, 12} {
+			assert.Equal(t, referenceLookUpTable(params, fs[1], messageModulus), eval.GenLookUpTableCustomFull(fs[1], messageModulus))
+		}
+	})
+
+	t.Run("GenCompressLUT", func(t *testing.T) {
+		lut := tfhe.NewLookUpTableCustom[T](1, params.BasePolyDegree())
+		eval.GenCompressLUTAssign(lut)
+		assert.Equal(t, referenceCompressLUT(params, params.BaseMessageModulus(), params.BasePolyDegree(), 1, params.BasePolyDegree()), lut)
+
+		lut = tfhe.NewLookUpTableCustom[T](params.PolyExtendFactor(), params.PolyDegree())
+		eval.GenExtendedCompressLUTAssign(lut)
+		assert.Equal(t, referenceCompressLUT(params, params.MessageModulus(), params.LookUpTableSize(), params.PolyExtendFactor(), params.PolyDegree()), lut)
+	})
+
+	t.Run("GenExtendedFDFBLookUpTable", func(t *testing.T) {
+		lut := tfhe.NewLookUpTableCustom[T](params.PolyExtendFactor(), params.PolyDegree())
+		eval.GenExtendedFDFBLookUpTableCustomFullAssign(fs[1], params.MessageModulus(), lut)
+		lutRaw := referenceCells(fs[1], int(params.MessageModulus()), params.LookUpTableSize(), true)
+		assert.Equal(t, referenceInterleave(lutRaw, params.PolyExtendFactor(), params.PolyDegree()), lut)
+	})
+
+	t.Run("GenLookUpTableNegDecomposed", func(t *testing.T) {
+		// The base LUT is sized by the given message modulus, not the one of the parameters.
+		messageModuli := []T{params.MessageModulus()}
+		if params.BaseMessageModulus() > 2 {
+			messageModuli = append(messageModuli, params.MessageModulus()/2)
+		}
+
+		for _, messageModulus := range messageModuli {
+			if params.PolyExtendFactor() > 1 {
+				lut := eval.NewDecomposedLutEBS()
+				eval.GenLookUpTableNegDecomposedEBSFullAssign(fs[1], messageModulus, &lut)
+				assert.Equal(t, referenceDecomposedLUT(params, fs[1:2], messageModulus, true), lut)
+				continue
+			}
+
+			lut := eval.NewDecomposedLut()
+			for n := 1; n <= len(fs); n++ {
+				eval.GenLookUpTableNegDecomposedMultiValueFullAssign(fs[:n], messageModulus, &lut)
+				assert.Equal(t, referenceDecomposedLUT(params, fs[:n], messageModulus, false), lut)
+			}
+		}
+	})
+}
+
+func TestLookUpTableBuilder(t *testing.T) {
+	for _, paramsList := range [][]tfhe.ParametersLiteral[uint64]{paramsListNew, paramsListNewEBS} {
+		for _, params := range paramsList {
+			params := params.Compile()
+			t.Run(fmt.Sprintf("Equivalence/ExtendFactor=%v/Prec=%v", params.PolyExtendFactor(), num.Log2(params.MessageModulus())), func(t *testing.T) {
+				testLookUpTableBuilderEquivalence(t, params)
+			})
+		}
+	}
+
+	for _, params := range paramsListUint32 {
+		params := params.Compile()
+		t.Run(fmt.Sprintf("Equivalence/Uint32/Prec=%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			testLookUpTableBuilderEquivalence(t, params)
+		})
+	}
+
+	t.Run("Mirror", func(t *testing.T) {
+		b := tfhe.NewLookUpTableBuilder[uint64](1, 16, tfhe.NegacyclicMirror, tfhe.OffsetCellCenter)
+		lut := b.Build(func(x int) uint64 { return uint64(x + 1) }, 4)
+		neg := func(x uint64) uint64 { return -x }
+		m3, m4 := neg(3), neg(4)
+		assert.Equal(t, []uint64{1, 1, 1, 1, 2, 2, 2, 2, m4, m4, m4, m4, m3, m3, m3, m3}, lut.Value[0].Coeffs)
+	})
+
+	t.Run("MultiValue", func(t *testing.T) {
+		b := tfhe.NewLookUpTableBuilder[uint64](1, 16, tfhe.NegacyclicPadding, tfhe.OffsetCellStart)
+		lut := b.BuildMultiValue([]func(int) uint64{func(x int) uint64 { return 1 }, func(x int) uint64 { return 2 }, func(x int) uint64 { return 3 }}, 2)
+		lutRaw := []uint64{1, 2, 2, 3, 3, 0, 0, 1, 1, 2, 2, 3, 3, 0, 0, ^uint64(0)}
+		assert.Equal(t, lutRaw, lut.Value[0].Coeffs)
+
+		b.ExtendFactor, b.PolyDegree = 2, 16
+		lut = b.BuildMultiValue([]func(int) uint64{func(x int) uint64 { return uint64(x) }}, 4)
+		assert.Equal(t, b.Build(func(x int) uint64 { return uint64(x) }, 4), lut)
+		assert.Equal(t, []uint64{0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3, 0, 0}, lut.Value[0].Coeffs)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		b := tfhe.NewLookUpTableBuilder[uint64](1, 16, tfhe.NegacyclicPadding, tfhe.OffsetCellStart)
+		assert.Panics(t, func() { b.BuildMultiValue(nil, 4) })
+		assert.Panics(t, func() {
+			b.BuildMultiValue([]func(int) uint64{func(int) uint64 { return 0 }, func(int) uint64 { return 0 }, func(int) uint64 { return 0 }}, 8)
+		})
+	})
+}
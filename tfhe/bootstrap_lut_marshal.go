@@ -0,0 +1,372 @@
+package tfhe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/math/poly"
+)
+
+// ByteSize returns the size of the LUT in bytes.
+func (lut LookUpTable[T]) ByteSize() int {
+	extendFactor := len(lut.Value)
+	polyDegree := lut.Value[0].Degree()
+
+	return 24 + extendFactor*polyDegree*num.ByteSizeT[T]()
+}
+
+// parametersFingerprint returns the fingerprint of the parameters of the LUT,
+// or zero if the LUT was created by [NewLookUpTableCustom].
+func (lut LookUpTable[T]) parametersFingerprint() uint64 {
+	if lut.Parameters == (Parameters[T]{}) {
+		return 0
+	}
+	return lut.Parameters.Fingerprint()
+}
+
+// headerWriteTo writes the header.
+func (lut LookUpTable[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], lut.parametersFingerprint())
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	extendFactor := len(lut.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(extendFactor))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	polyDegree := lut.Value[0].Degree()
+	binary.BigEndian.PutUint64(buf[:], uint64(polyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (lut LookUpTable[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	polyDegree := lut.Value[0].Degree()
+	buf := make([]byte, polyDegree*num.ByteSizeT[T]())
+
+	for i := range lut.Value {
+		if nWrite, err = vecWriteToBuffered(lut.Value[i].Coeffs, buf, w); err != nil {
+			return n + nWrite, err
+		}
+		n += nWrite
+	}
+
+	return
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] ParametersFingerprint
+//	[8] ExtendFactor
+//	[8] PolyDegree
+//	    Value
+//
+// ParametersFingerprint is zero if the LUT was created by [NewLookUpTableCustom].
+func (lut LookUpTable[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = lut.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = lut.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(lut.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+// If size is positive, the header should have ExtendFactor * PolyDegree equal to size,
+// and if extendFactor is positive, ExtendFactor equal to extendFactor.
+// This is checked before the value is allocated.
+func (lut *LookUpTable[T]) headerReadFrom(r io.Reader, extendFactor, size int) (n int64, err error) {
+	var nRead int
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	if binary.BigEndian.Uint64(buf[:]) != lut.parametersFingerprint() {
+		return n, errors.New("LookUpTable built for different parameters")
+	}
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	extendFactorRead := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	polyDegree := int(binary.BigEndian.Uint64(buf[:]))
+
+	switch {
+	case extendFactorRead < 1:
+		return n, errors.New("ExtendFactor smaller than one")
+	case polyDegree < poly.MinDegree || !num.IsPowerOfTwo(polyDegree):
+		return n, errors.New("PolyDegree not valid")
+	case extendFactorRead > math.MaxInt/polyDegree:
+		return n, errors.New("ExtendFactor * PolyDegree too large")
+	case extendFactor > 0 && extendFactorRead != extendFactor:
+		return n, errors.New("LookUpTable size mismatch")
+	case size > 0 && extendFactorRead*polyDegree != size:
+		return n, errors.New("LookUpTable size mismatch")
+	}
+
+	params := lut.Parameters
+	if params != (Parameters[T]{}) && (extendFactorRead != params.polyExtendFactor || polyDegree != params.polyDegree) {
+		return n, errors.New("LookUpTable size mismatch")
+	}
+
+	*lut = NewLookUpTableCustom[T](extendFactorRead, polyDegree)
+	lut.Parameters = params
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (lut *LookUpTable[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	polyDegree := lut.Value[0].Degree()
+	buf := make([]byte, polyDegree*num.ByteSizeT[T]())
+
+	for i := range lut.Value {
+		if nRead, err = vecReadFromBuffered(lut.Value[i].Coeffs, buf, r); err != nil {
+			return n + nRead, err
+		}
+		n += nRead
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+//
+// If the LUT was created by [NewLookUpTable], it can only read LUTs built for the same parameters.
+// Otherwise, it can only read LUTs created by [NewLookUpTableCustom].
+func (lut *LookUpTable[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	return lut.readFromSize(r, 0, 0)
+}
+
+// readFromSize is equivalent to [*LookUpTable.ReadFrom],
+// but returns an error before allocating if the size in the header does not match.
+// See [*LookUpTable.headerReadFrom] for extendFactor and size.
+func (lut *LookUpTable[T]) readFromSize(r io.Reader, extendFactor, size int) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = lut.headerReadFrom(r, extendFactor, size); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = lut.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (lut LookUpTable[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, lut.ByteSize()))
+	_, err = lut.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (lut *LookUpTable[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := lut.ReadFrom(buf)
+	return err
+}
+
+// ByteSize returns the size of the decomposed LUT in bytes.
+func (lut DecomposedLookUpTable[T]) ByteSize() int {
+	size := 32 + lut.BaseLUT.ByteSize()
+	for i := range lut.NegLUTs {
+		size += lut.NegLUTs[i].ByteSize()
+	}
+	return size
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] ParametersFingerprint
+//	[8] MessageModulus
+//	[8] ValueCount
+//	[8] len(NegLUTs)
+//	    NegLUTs
+//	    BaseLUT
+func (lut DecomposedLookUpTable[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], lut.Parameters.Fingerprint())
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	binary.BigEndian.PutUint64(buf[:], uint64(lut.MessageModulus))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	binary.BigEndian.PutUint64(buf[:], uint64(lut.ValueCount))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	binary.BigEndian.PutUint64(buf[:], uint64(len(lut.NegLUTs)))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	for i := range lut.NegLUTs {
+		if nWrite64, err = lut.NegLUTs[i].WriteTo(w); err != nil {
+			return n + nWrite64, err
+		}
+		n += nWrite64
+	}
+
+	if nWrite64, err = lut.BaseLUT.WriteTo(w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	if n < int64(lut.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+//
+// Parameters of lut should be set before reading,
+// for example by [NewDecomposedLookUpTable].
+// Returns an error when the encoded LUT was built for different parameters,
+// or when its header does not fit the parameters.
+func (lut *DecomposedLookUpTable[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	if binary.BigEndian.Uint64(buf[:]) != lut.Parameters.Fingerprint() {
+		return n, errors.New("DecomposedLookUpTable built for different parameters")
+	}
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	messageModulus := binary.BigEndian.Uint64(buf[:])
+	switch {
+	case messageModulus < 2*uint64(lut.Parameters.baseExtendFactor):
+		return n, errors.New("MessageModulus smaller than 2 * BaseExtendFactor")
+	case messageModulus > uint64(lut.Parameters.lookUpTableSize):
+		return n, errors.New("MessageModulus larger than LookUpTableSize")
+	}
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	valueCount := binary.BigEndian.Uint64(buf[:])
+	switch {
+	case valueCount < 1:
+		return n, errors.New("ValueCount smaller than one")
+	case valueCount > uint64(lut.Parameters.lookUpTableSize) || multiValueSlotCount(int(valueCount)) > lut.Parameters.lookUpTableSize/int(messageModulus):
+		return n, errors.New("ValueCount larger than LookUpTableSize / MessageModulus")
+	}
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	negLUTCount := int(binary.BigEndian.Uint64(buf[:]))
+	if negLUTCount != num.Log2(lut.Parameters.baseExtendFactor) {
+		return n, errors.New("NegLUTs length not equal to log(BaseExtendFactor)")
+	}
+
+	negLUTs := make([]LookUpTable[T], negLUTCount)
+	for i := range negLUTs {
+		if nRead64, err = negLUTs[i].readFromSize(r, 0, lut.Parameters.lookUpTableSize>>(i+1)); err != nil {
+			return n + nRead64, err
+		}
+		n += nRead64
+	}
+
+	var baseLUT LookUpTable[T]
+	if nRead64, err = baseLUT.readFromSize(r, 1, lut.Parameters.basePolyDegree); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	lut.NegLUTs = negLUTs
+	lut.BaseLUT = baseLUT
+	lut.MessageModulus = T(messageModulus)
+	lut.ValueCount = int(valueCount)
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (lut DecomposedLookUpTable[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, lut.ByteSize()))
+	_, err = lut.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+//
+// Parameters of lut should be set before unmarshaling,
+// for example by [NewDecomposedLookUpTable].
+func (lut *DecomposedLookUpTable[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := lut.ReadFrom(buf)
+	return err
+}
@@ -1,192 +1,307 @@
 package tfhe
 
 import (
-	"math"
-
 	"github.com/sp301415/tfhe-go/math/num"
-	"github.com/sp301415/tfhe-go/math/vec"
 )
 
-func (e *Evaluator[T]) NewDecomposedLutEBS() []LookUpTable[T] {
-	length := int(math.Log2(float64(e.Parameters.lookUpTableSize/2048))) + 1
-	decomposedLut := make([]LookUpTable[T], length)
-	//gen NegLUT
-	for i := 0; i < length-1; i++ {
-		decomposedLut[i] = NewLookUpTableCustom[T](1<<(length-i-2), 2048)
+// DecomposedLookUpTable is a lookup table for full-domain functional bootstrapping.
+// A full-domain function is decomposed into negacyclic functions and a base function,
+// each evaluated by its own blind rotation.
+type DecomposedLookUpTable[T TorusInt] struct {
+	// NegLUTs are the LUTs of the negacyclic functions.
+	// NegLUTs[i] has size LookUpTableSize / 2^(i+1).
+	// This has length log(BaseExtendFactor).
+	NegLUTs []LookUpTable[T]
+	// BaseLUT is the LUT of the base function.
+	// This has size BasePolyDegree.
+	BaseLUT LookUpTable[T]
+
+	// MessageModulus is the message modulus this LUT was generated for.
+	MessageModulus T
+	// ValueCount is the number of functions packed in this LUT.
+	// This is one, unless generated by [*Evaluator.GenLookUpTableNegDecomposedMultiValueAssign].
+	ValueCount int
+	// Parameters is the parameters this LUT was built for.
+	Parameters Parameters[T]
+}
+
+// NewDecomposedLookUpTable creates a new decomposed lookup table
+// for the evaluator hierarchy, where NegLUTs[i] is a single polynomial.
+//
+// Panics when MessageModulus is smaller than 2 * BaseExtendFactor.
+func NewDecomposedLookUpTable[T TorusInt](params Parameters[T]) DecomposedLookUpTable[T] {
+	if params.BaseMessageModulus() < 2 {
+		panic("MessageModulus smaller than 2 * BaseExtendFactor")
+	}
+
+	negLUTs := make([]LookUpTable[T], num.Log2(params.baseExtendFactor))
+	for i := range negLUTs {
+		negLUTs[i] = NewLookUpTableCustom[T](1, params.lookUpTableSize>>(i+1))
+	}
+
+	return DecomposedLookUpTable[T]{
+		NegLUTs:        negLUTs,
+		BaseLUT:        NewLookUpTableCustom[T](1, params.basePolyDegree),
+		MessageModulus: params.messageModulus,
+		ValueCount:     1,
+		Parameters:     params,
 	}
-	//gen BaseLUT
-	decomposedLut[length-1] = NewLookUpTableCustom[T](1, 2048)
-	return decomposedLut
 }
-func (e *Evaluator[T]) NewDecomposedLut() []LookUpTable[T] {
-	length := int(math.Log2(float64(e.Parameters.lookUpTableSize/2048))) + 1
-	decomposedLut := make([]LookUpTable[T], length)
-	//gen NegLUT
-	for i := 0; i < length-1; i++ {
-		decomposedLut[i] = NewLookUpTableCustom[T](1, 2048<<(length-i-2))
+
+// NewDecomposedLookUpTableEBS creates a new decomposed lookup table
+// for extended bootstrapping, where NegLUTs[i] is an extended LUT over the base ring.
+//
+// Panics when BasePolyDegree is not equal to PolyDegree,
+// or when MessageModulus is smaller than 2 * BaseExtendFactor.
+func NewDecomposedLookUpTableEBS[T TorusInt](params Parameters[T]) DecomposedLookUpTable[T] {
+	if params.basePolyDegree != params.polyDegree {
+		panic("BasePolyDegree not equal to PolyDegree")
+	}
+	if params.BaseMessageModulus() < 2 {
+		panic("MessageModulus smaller than 2 * BaseExtendFactor")
+	}
+
+	negLUTs := make([]LookUpTable[T], num.Log2(params.baseExtendFactor))
+	for i := range negLUTs {
+		negLUTs[i] = NewLookUpTableCustom[T](params.baseExtendFactor>>(i+1), params.basePolyDegree)
+	}
+
+	return DecomposedLookUpTable[T]{
+		NegLUTs:        negLUTs,
+		BaseLUT:        NewLookUpTableCustom[T](1, params.basePolyDegree),
+		MessageModulus: params.messageModulus,
+		ValueCount:     1,
+		Parameters:     params,
 	}
-	//gen BaseLUT
-	decomposedLut[length-1] = NewLookUpTableCustom[T](1, 2048)
-	return decomposedLut
 }
 
-func (e *Evaluator[T]) GenLookUpTableNegDecomposedEBSAssign(f func(int) int, messageModulus, scale T, decomposedLutOut []LookUpTable[T]) {
-	e.GenLookUpTableNegDecomposedEBSFullAssign(func(x int) T { return e.EncodeLWECustom(f(x), messageModulus, scale).Value }, messageModulus, decomposedLutOut)
+// NewDecomposedLut creates a new decomposed lookup table for the evaluator hierarchy.
+func (e *Evaluator[T]) NewDecomposedLut() DecomposedLookUpTable[T] {
+	return NewDecomposedLookUpTable(e.Parameters)
 }
-func (e *Evaluator[T]) GenLookUpTableNegDecomposedEBSFullAssign(f func(int) T, messageModulus T, decomposedLutOut []LookUpTable[T]) {
-	extendFactor := e.Parameters.lookUpTableSize / 2048 // Assuming 2048 is the base poly degree
-	polyDegree := 2048
-	logExtendFactor := int(math.Log2(float64(extendFactor)))
-	// decompose func to negacyclic functions and a base function
-	funcEval := make([]T, int(messageModulus))
-	for x := 0; x < int(messageModulus); x++ {
-		funcEval[x] = f(x) //% messageModulus
+
+// NewDecomposedLutEBS creates a new decomposed lookup table for extended bootstrapping.
+func (e *Evaluator[T]) NewDecomposedLutEBS() DecomposedLookUpTable[T] {
+	return NewDecomposedLookUpTableEBS(e.Parameters)
+}
+
+// Copy returns a copy of the decomposed LUT.
+func (lut DecomposedLookUpTable[T]) Copy() DecomposedLookUpTable[T] {
+	negLUTs := make([]LookUpTable[T], len(lut.NegLUTs))
+	for i := range negLUTs {
+		negLUTs[i] = lut.NegLUTs[i].Copy()
 	}
-	var decomposedNegFuncEval [][]T
-	currentFuncEval := funcEval
-	for i := 0; i < logExtendFactor; i++ {
-		n := len(currentFuncEval) / 2
-		newFuncEval := make([]T, n)
-		negFuncEval := make([]T, n)
 
-		for j := 0; j < n; j++ {
-			newFuncEval[j] = currentFuncEval[j]/2 + currentFuncEval[j+n]/2
-			negFuncEval[j] = currentFuncEval[j]/2 - currentFuncEval[j+n]/2
-		}
-		decomposedNegFuncEval = append(decomposedNegFuncEval, negFuncEval)
-		currentFuncEval = newFuncEval
+	return DecomposedLookUpTable[T]{
+		NegLUTs:        negLUTs,
+		BaseLUT:        lut.BaseLUT.Copy(),
+		MessageModulus: lut.MessageModulus,
+		ValueCount:     lut.ValueCount,
+		Parameters:     lut.Parameters,
 	}
+}
 
-	baseFuncEval := currentFuncEval
-	//generate NegLUT from decomposed func
-	for k := 0; k < len(decomposedNegFuncEval); k++ {
-		length := e.Parameters.lookUpTableSize / (1 << (k + 1))
-		lutRaw := make([]T, length)
-		extendFactor := length / 2048
-		for x := 0; x < int(messageModulus)/(1<<(k+1)); x++ {
-			start := num.DivRound(x*e.Parameters.lookUpTableSize, int(messageModulus))
-			end := num.DivRound((x+1)*e.Parameters.lookUpTableSize, int(messageModulus))
-			y := decomposedNegFuncEval[k][x]
-			for xx := start; xx < end; xx++ {
-				lutRaw[xx] = y
-
-			}
-		}
-		offset := num.DivRound(e.Parameters.lookUpTableSize, int(2*messageModulus))
-		vec.RotateInPlace(lutRaw, -offset)
-		for i := length - offset; i < length; i++ {
-			lutRaw[i] = -lutRaw[i]
-		}
-		for i := 0; i < extendFactor; i++ {
-			for j := 0; j < polyDegree; j++ {
-				decomposedLutOut[k].Value[i].Coeffs[j] = lutRaw[j*extendFactor+i]
-			}
-		}
+// CopyFrom copies values from the decomposed LUT.
+func (lut *DecomposedLookUpTable[T]) CopyFrom(lutIn DecomposedLookUpTable[T]) {
+	for i := range lut.NegLUTs {
+		lut.NegLUTs[i].CopyFrom(lutIn.NegLUTs[i])
 	}
+	lut.BaseLUT.CopyFrom(lutIn.BaseLUT)
+	lut.MessageModulus = lutIn.MessageModulus
+	lut.ValueCount = lutIn.ValueCount
+	lut.Parameters = lutIn.Parameters
+}
 
-	// generate BaseLUT for FDFB from base func
-	baseMessageModulus := e.Parameters.messageModulus / T(extendFactor)
-	length := polyDegree
-	lutRaw := make([]T, length)
-
-	for x := T(0); x < baseMessageModulus/2; x++ {
-		start := num.DivRound(int(x)*polyDegree, int(baseMessageModulus))
-		end := num.DivRound((int(x)+1)*polyDegree, int(baseMessageModulus))
-		y := baseFuncEval[int(x)]
-		for xx := start; xx < end; xx++ {
-			lutRaw[xx] = y
-		}
+// Clear clears the decomposed LUT.
+func (lut *DecomposedLookUpTable[T]) Clear() {
+	for i := range lut.NegLUTs {
+		lut.NegLUTs[i].Clear()
+	}
+	lut.BaseLUT.Clear()
+}
+
+// checkDecomposedLUT panics if lut was not built for params.
+// If extended is true, NegLUTs should be extended LUTs over the base ring,
+// as created by [NewDecomposedLookUpTableEBS].
+// Otherwise, NegLUTs should be single polynomials, as created by [NewDecomposedLookUpTable].
+func checkDecomposedLUT[T TorusInt](lut DecomposedLookUpTable[T], params Parameters[T], extended bool) {
+	if lut.Parameters != params {
+		panic("DecomposedLookUpTable built for different parameters")
 	}
-	for x := baseMessageModulus / 2; x < baseMessageModulus; x++ {
-		start := num.DivRound(int(x)*polyDegree, int(baseMessageModulus))
-		end := num.DivRound((int(x)+1)*polyDegree, int(baseMessageModulus))
-		y := baseFuncEval[int(baseMessageModulus-x+baseMessageModulus/2-1)]
-		for xx := start; xx < end; xx++ {
-			lutRaw[xx] = -y
+	if len(lut.NegLUTs) != num.Log2(params.baseExtendFactor) {
+		panic("NegLUTs length not equal to log(BaseExtendFactor)")
+	}
+
+	for i, negLUT := range lut.NegLUTs {
+		polyDegree := params.lookUpTableSize >> (i + 1)
+		if extended {
+			polyDegree = params.basePolyDegree
+		}
+		if negLUT.Value[0].Degree() != polyDegree || len(negLUT.Value)*polyDegree != params.lookUpTableSize>>(i+1) {
+			panic("NegLUTs size mismatch")
 		}
 	}
+	if len(lut.BaseLUT.Value) != 1 || lut.BaseLUT.Value[0].Degree() != params.basePolyDegree {
+		panic("BaseLUT size mismatch")
+	}
+	if lut.ValueCount < 1 {
+		panic("ValueCount smaller than one")
+	}
+}
 
-	for j := 0; j < polyDegree; j++ {
-		decomposedLutOut[logExtendFactor].Value[0].Coeffs[j] = lutRaw[j]
+// multiValueSlotCount returns the number of slots each message cell is split into
+// when packing valueCount functions in one LUT.
+// This is the smallest power of two not smaller than valueCount.
+func multiValueSlotCount(valueCount int) int {
+	slotCount := 1
+	for slotCount < valueCount {
+		slotCount <<= 1
 	}
+	return slotCount
 }
 
-func (e *Evaluator[T]) GenLookUpTableNegDecomposedAssign(f func(int) int, messageModulus, scale T, decomposedLutOut []LookUpTable[T]) {
-	e.GenLookUpTableNegDecomposedFullAssign(func(x int) T { return e.EncodeLWECustom(f(x), messageModulus, scale).Value }, messageModulus, decomposedLutOut)
+// valueIndex returns the coefficient index where the j-th function of lut is read
+// after blind rotation.
+func (lut DecomposedLookUpTable[T]) valueIndex(j int) int {
+	slotCount := multiValueSlotCount(lut.ValueCount)
+	return num.DivRound(j*lut.Parameters.lookUpTableSize, int(lut.MessageModulus)*slotCount)
+}
+
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedEBSAssign(f func(int) int, messageModulus, scale T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	e.GenLookUpTableNegDecomposedEBSFullAssign(func(x int) T { return e.EncodeLWECustom(f(x), messageModulus, scale).Value }, messageModulus, decomposedLutOut)
 }
-func (e *Evaluator[T]) GenLookUpTableNegDecomposedFullAssign(f func(int) T, messageModulus T, decomposedLutOut []LookUpTable[T]) {
-	extendFactor := e.Parameters.lookUpTableSize / 2048 // Assuming 2048 is the base poly degree
-	polyDegree := 2048
-	logExtendFactor := int(math.Log2(float64(extendFactor)))
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedEBSFullAssign(f func(int) T, messageModulus T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	checkDecomposedLUT(*decomposedLutOut, e.Parameters, true)
+	if messageModulus < 2*T(e.Parameters.baseExtendFactor) {
+		panic("MessageModulus smaller than 2 * BaseExtendFactor")
+	}
+	decomposedLutOut.MessageModulus = messageModulus
+	decomposedLutOut.ValueCount = 1
+
+	extendFactor := e.Parameters.baseExtendFactor
+	polyDegree := e.Parameters.basePolyDegree
 	// decompose func to negacyclic functions and a base function
-	funcEval := make([]T, int(messageModulus))
+	decomposedNegFuncEval, baseFuncEval := decomposeNegacyclicFunc(f, messageModulus, num.Log2(extendFactor))
+
+	//generate NegLUT from decomposed func
+	for k := range decomposedNegFuncEval {
+		negFuncEval := decomposedNegFuncEval[k]
+		lutExtendFactor := e.Parameters.lookUpTableSize / (1 << (k + 1)) / polyDegree
+		e.lookUpTableBuilder(lutExtendFactor, polyDegree, NegacyclicPadding, OffsetCellStart).BuildAssign(func(x int) T { return negFuncEval[x] }, messageModulus>>(k+1), decomposedLutOut.NegLUTs[k])
+	}
+
+	// generate BaseLUT for FDFB from base func
+	baseMessageModulus := messageModulus / T(extendFactor)
+	e.lookUpTableBuilder(1, polyDegree, NegacyclicMirror, OffsetCellCenter).BuildAssign(func(x int) T { return baseFuncEval[x] }, baseMessageModulus, decomposedLutOut.BaseLUT)
+}
+
+// decomposeNegacyclicFunc decomposes f into logExtendFactor negacyclic functions and a base function,
+// and returns their evaluations.
+// The i-th negacyclic function has messageModulus / 2^(i+1) inputs,
+// and the base function has messageModulus / 2^logExtendFactor inputs.
+func decomposeNegacyclicFunc[T TorusInt](f func(int) T, messageModulus T, logExtendFactor int) (negFuncEval [][]T, baseFuncEval []T) {
+	currentFuncEval := make([]T, int(messageModulus))
 	for x := 0; x < int(messageModulus); x++ {
-		funcEval[x] = f(x) //% messageModulus
+		currentFuncEval[x] = f(x)
 	}
-	var decomposedNegFuncEval [][]T
-	currentFuncEval := funcEval
+
+	negFuncEval = make([][]T, logExtendFactor)
 	for i := 0; i < logExtendFactor; i++ {
 		n := len(currentFuncEval) / 2
 		newFuncEval := make([]T, n)
-		negFuncEval := make([]T, n)
+		negFuncEval[i] = make([]T, n)
 
 		for j := 0; j < n; j++ {
 			newFuncEval[j] = currentFuncEval[j]/2 + currentFuncEval[j+n]/2
-			negFuncEval[j] = currentFuncEval[j]/2 - currentFuncEval[j+n]/2
+			negFuncEval[i][j] = currentFuncEval[j]/2 - currentFuncEval[j+n]/2
 		}
-		decomposedNegFuncEval = append(decomposedNegFuncEval, negFuncEval)
 		currentFuncEval = newFuncEval
 	}
 
-	baseFuncEval := currentFuncEval
-	//generate NegLUT from decomposed func
-	for k := 0; k < len(decomposedNegFuncEval); k++ {
-		length := e.Parameters.lookUpTableSize / (1 << (k + 1))
-		lutRaw := make([]T, length)
-		for x := 0; x < int(messageModulus)/(1<<(k+1)); x++ {
-			start := num.DivRound(x*e.Parameters.lookUpTableSize, int(messageModulus))
-			end := num.DivRound((x+1)*e.Parameters.lookUpTableSize, int(messageModulus))
-			y := decomposedNegFuncEval[k][x]
-			for xx := start; xx < end; xx++ {
-				lutRaw[xx] = y
-
-			}
-		}
-		offset := num.DivRound(e.Parameters.lookUpTableSize, int(2*messageModulus))
-		vec.RotateInPlace(lutRaw, -offset)
-		for i := length - offset; i < length; i++ {
-			lutRaw[i] = -lutRaw[i]
-		}
-		for i := 0; i < 1; i++ {
-			for j := 0; j < length; j++ {
-				decomposedLutOut[k].Value[i].Coeffs[j] = lutRaw[j]
-			}
-		}
+	return negFuncEval, currentFuncEval
+}
+
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedAssign(f func(int) int, messageModulus, scale T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	e.GenLookUpTableNegDecomposedFullAssign(func(x int) T { return e.EncodeLWECustom(f(x), messageModulus, scale).Value }, messageModulus, decomposedLutOut)
+}
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedFullAssign(f func(int) T, messageModulus T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	e.GenLookUpTableNegDecomposedMultiValueFullAssign([]func(int) T{f}, messageModulus, decomposedLutOut)
+}
+
+// GenLookUpTableNegDecomposedMultiValueAssign generates a decomposed LUT packing functions fs
+// and writes it to decomposedLutOut.
+// Input and output of each function is cut by messageModulus.
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedMultiValueAssign(fs []func(int) int, messageModulus, scale T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	fsFull := make([]func(int) T, len(fs))
+	for i := range fs {
+		f := fs[i]
+		fsFull[i] = func(x int) T { return e.EncodeLWECustom(f(x), messageModulus, scale).Value }
 	}
+	e.GenLookUpTableNegDecomposedMultiValueFullAssign(fsFull, messageModulus, decomposedLutOut)
+}
 
-	// generate BaseLUT for FDFB from base func
-	baseMessageModulus := e.Parameters.messageModulus / T(extendFactor)
-	length := polyDegree
-	lutRaw := make([]T, length)
-
-	for x := T(0); x < baseMessageModulus/2; x++ {
-		start := num.DivRound(int(x)*polyDegree, int(baseMessageModulus))
-		end := num.DivRound((int(x)+1)*polyDegree, int(baseMessageModulus))
-		y := baseFuncEval[int(x)]
-		for xx := start; xx < end; xx++ {
-			lutRaw[xx] = y
+// GenLookUpTableNegDecomposedMultiValueFullAssign generates a decomposed LUT packing functions fs
+// and writes it to decomposedLutOut.
+//
+// Each message cell of the LUTs is split into slots, one for each function,
+// so that a single blind rotation evaluates every function at once.
+// The number of slots is the smallest power of two not smaller than len(fs),
+// and the error tolerance of bootstrapping is divided by it.
+//
+// Panics when len(fs) is smaller than one,
+// when messageModulus is smaller than 2 * BaseExtendFactor,
+// or when the number of slots is larger than LookUpTableSize / messageModulus.
+func (e *Evaluator[T]) GenLookUpTableNegDecomposedMultiValueFullAssign(fs []func(int) T, messageModulus T, decomposedLutOut *DecomposedLookUpTable[T]) {
+	checkDecomposedLUT(*decomposedLutOut, e.Parameters, false)
+
+	valueCount := len(fs)
+	slotCount := multiValueSlotCount(valueCount)
+	switch {
+	case valueCount < 1:
+		panic("Number of functions smaller than one")
+	case messageModulus < 2*T(e.Parameters.baseExtendFactor):
+		panic("MessageModulus smaller than 2 * BaseExtendFactor")
+	case slotCount > e.Parameters.lookUpTableSize/int(messageModulus):
+		panic("Number of functions larger than LookUpTableSize / MessageModulus")
+	}
+
+	decomposedLutOut.MessageModulus = messageModulus
+	decomposedLutOut.ValueCount = valueCount
+
+	extendFactor := e.Parameters.baseExtendFactor
+	polyDegree := e.Parameters.basePolyDegree
+	logExtendFactor := num.Log2(extendFactor)
+	// decompose each func to negacyclic functions and a base function
+	decomposedNegFuncEval := make([][][]T, logExtendFactor)
+	baseFuncEval := make([][]T, valueCount)
+	for v, f := range fs {
+		negFuncEval, currentFuncEval := decomposeNegacyclicFunc(f, messageModulus, logExtendFactor)
+		for i := range negFuncEval {
+			decomposedNegFuncEval[i] = append(decomposedNegFuncEval[i], negFuncEval[i])
 		}
+		baseFuncEval[v] = currentFuncEval
 	}
-	for x := baseMessageModulus / 2; x < baseMessageModulus; x++ {
-		start := num.DivRound(int(x)*polyDegree, int(baseMessageModulus))
-		end := num.DivRound((int(x)+1)*polyDegree, int(baseMessageModulus))
-		y := baseFuncEval[int(baseMessageModulus-x+baseMessageModulus/2-1)]
-		for xx := start; xx < end; xx++ {
-			lutRaw[xx] = -y
+
+	//generate NegLUT from decomposed funcs
+	negFs := make([]func(int) T, valueCount)
+	for k := 0; k < logExtendFactor; k++ {
+		for v := range negFs {
+			negFuncEval := decomposedNegFuncEval[k][v]
+			negFs[v] = func(x int) T { return negFuncEval[x] }
 		}
+		// Each slot is centered at the input, which is a multiple of the cell width.
+		length := e.Parameters.lookUpTableSize / (1 << (k + 1))
+		e.lookUpTableBuilder(1, length, NegacyclicPadding, OffsetCellStart).BuildMultiValueAssign(negFs, messageModulus>>(k+1), decomposedLutOut.NegLUTs[k])
 	}
 
-	for j := 0; j < polyDegree; j++ {
-		decomposedLutOut[logExtendFactor].Value[0].Coeffs[j] = lutRaw[j]
+	// generate BaseLUT for FDFB from base funcs
+	// After the compress bootstrap, the input is at the center of the cell,
+	// so the slots are shifted to be centered there.
+	baseFs := make([]func(int) T, valueCount)
+	for v := range baseFs {
+		baseFuncEval := baseFuncEval[v]
+		baseFs[v] = func(x int) T { return baseFuncEval[x] }
 	}
+	baseMessageModulus := messageModulus / T(extendFactor)
+	e.lookUpTableBuilder(1, polyDegree, NegacyclicMirror, OffsetCellCenter).BuildMultiValueAssign(baseFs, baseMessageModulus, decomposedLutOut.BaseLUT)
 }
@@ -0,0 +1,149 @@
+package tfhe_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecomposedLookUpTable(t *testing.T) {
+	params := tfhe.Params6.Compile()
+	paramsEBS := tfhe.ParamsEBS6.Compile()
+
+	t.Run("Shape", func(t *testing.T) {
+		lut := tfhe.NewDecomposedLookUpTable(params)
+		assert.Equal(t, num.Log2(params.BaseExtendFactor()), len(lut.NegLUTs))
+		for i, negLUT := range lut.NegLUTs {
+			assert.Equal(t, 1, len(negLUT.Value))
+			assert.Equal(t, params.LookUpTableSize()>>(i+1), negLUT.Value[0].Degree())
+		}
+		assert.Equal(t, params.BasePolyDegree(), lut.BaseLUT.Value[0].Degree())
+
+		lutEBS := tfhe.NewDecomposedLookUpTableEBS(paramsEBS)
+		assert.Equal(t, num.Log2(paramsEBS.BaseExtendFactor()), len(lutEBS.NegLUTs))
+		for i, negLUT := range lutEBS.NegLUTs {
+			assert.Equal(t, paramsEBS.BaseExtendFactor()>>(i+1), len(negLUT.Value))
+			assert.Equal(t, paramsEBS.BasePolyDegree(), negLUT.Value[0].Degree())
+		}
+		assert.Panics(t, func() { tfhe.NewDecomposedLookUpTableEBS(params) })
+	})
+
+	t.Run("CopyClear", func(t *testing.T) {
+		eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint64]{})
+		lut := eval.NewDecomposedLut()
+		eval.GenLookUpTableNegDecomposedAssign(func(x int) int { return 2*x + 1 }, params.MessageModulus(), params.Scale(), &lut)
+
+		lutCopy := lut.Copy()
+		assert.Equal(t, lut, lutCopy)
+
+		lutCopy.Clear()
+		assert.NotEqual(t, lut, lutCopy)
+		for _, negLUT := range lutCopy.NegLUTs {
+			assert.Equal(t, make([]uint64, negLUT.Value[0].Degree()), negLUT.Value[0].Coeffs)
+		}
+
+		lutCopy.CopyFrom(lut)
+		assert.Equal(t, lut, lutCopy)
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		eval := tfhe.NewEvaluator(paramsEBS, tfhe.EvaluationKey[uint64]{})
+		lut := eval.NewDecomposedLutEBS()
+		eval.GenLookUpTableNegDecomposedEBSAssign(func(x int) int { return 3*x + 2 }, paramsEBS.MessageModulus(), paramsEBS.Scale(), &lut)
+
+		for _, negLUT := range lut.NegLUTs {
+			data, err := negLUT.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, negLUT.ByteSize(), len(data))
+
+			var negLUTOut tfhe.LookUpTable[uint64]
+			assert.NoError(t, negLUTOut.UnmarshalBinary(data))
+			assert.Equal(t, negLUT, negLUTOut)
+		}
+
+		data, err := lut.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, lut.ByteSize(), len(data))
+
+		lutOut := tfhe.NewDecomposedLookUpTableEBS(paramsEBS)
+		assert.NoError(t, lutOut.UnmarshalBinary(data))
+		assert.Equal(t, lut, lutOut)
+
+		lutOther := tfhe.NewDecomposedLookUpTable(params)
+		assert.Error(t, lutOther.UnmarshalBinary(data))
+		assert.Error(t, lutOut.UnmarshalBinary(data[:len(data)-1]))
+
+		// Malformed headers return errors instead of panicking.
+		for _, header := range []struct {
+			offset int
+			value  uint64
+		}{
+			{8, 0},           // MessageModulus
+			{8, 1 << 62},     // MessageModulus
+			{16, 0},          // ValueCount
+			{16, 1 << 62},    // ValueCount
+			{40, 0},          // NegLUTs[0].ExtendFactor
+			{40, 1 << 40},    // NegLUTs[0].ExtendFactor
+			{40, 1<<63 - 1},  // NegLUTs[0].ExtendFactor
+			{48, 0},          // NegLUTs[0].PolyDegree
+			{48, 1000},       // NegLUTs[0].PolyDegree
+			{32, 0xdeadbeef}, // NegLUTs[0].ParametersFingerprint
+			{len(data) - lut.BaseLUT.ByteSize() + 8, 1 << 40}, // BaseLUT.ExtendFactor
+		} {
+			dataMalformed := append([]byte(nil), data...)
+			binary.BigEndian.PutUint64(dataMalformed[header.offset:], header.value)
+			lutMalformed := tfhe.NewDecomposedLookUpTableEBS(paramsEBS)
+			assert.NotPanics(t, func() { assert.Error(t, lutMalformed.UnmarshalBinary(dataMalformed)) })
+		}
+	})
+
+	t.Run("MarshalLookUpTable", func(t *testing.T) {
+		eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint64]{})
+		lut := eval.GenLookUpTable(func(x int) int { return 2*x + 1 })
+		assert.Equal(t, params, lut.Parameters)
+
+		data, err := lut.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, lut.ByteSize(), len(data))
+
+		lutOut := tfhe.NewLookUpTable(params)
+		assert.NoError(t, lutOut.UnmarshalBinary(data))
+		assert.Equal(t, lut, lutOut)
+
+		// LUTs are only read against the parameters they were built for.
+		var lutCustom tfhe.LookUpTable[uint64]
+		assert.Error(t, lutCustom.UnmarshalBinary(data))
+		lutOther := tfhe.NewLookUpTable(tfhe.Params5.Compile())
+		assert.Error(t, lutOther.UnmarshalBinary(data))
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		f := func(x int) int { return x }
+		eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint64]{})
+		evalEBS := tfhe.NewEvaluator(paramsEBS, tfhe.EvaluationKey[uint64]{})
+
+		lut := tfhe.NewDecomposedLookUpTable(params)
+		lutEBS := tfhe.NewDecomposedLookUpTableEBS(paramsEBS)
+		assert.Panics(t, func() {
+			evalEBS.GenLookUpTableNegDecomposedEBSAssign(f, paramsEBS.MessageModulus(), paramsEBS.Scale(), &lut)
+		})
+		assert.Panics(t, func() { eval.GenLookUpTableNegDecomposedAssign(f, params.MessageModulus(), params.Scale(), &lutEBS) })
+
+		lutOther := tfhe.NewDecomposedLookUpTable(tfhe.Params5.Compile())
+		assert.Panics(t, func() { eval.GenLookUpTableNegDecomposedAssign(f, params.MessageModulus(), params.Scale(), &lutOther) })
+
+		messageModulusSmall := uint64(params.BaseExtendFactor())
+		assert.Panics(t, func() { eval.GenLookUpTableNegDecomposedAssign(f, messageModulusSmall, params.Scale(), &lut) })
+		assert.Panics(t, func() {
+			evalEBS.GenLookUpTableNegDecomposedEBSAssign(f, messageModulusSmall, paramsEBS.Scale(), &lutEBS)
+		})
+
+		evk := tfhe.HierarchicalEvaluationKey[uint64]{Parameters: params, Value: make([]tfhe.EvaluationKey[uint64], params.HierarchyDepth())}
+		fdEval := tfhe.NewFullDomainEvaluator(params, evk)
+		ct := tfhe.NewLWECiphertext(params)
+		assert.Panics(t, func() { fdEval.BootstrapLUT(ct, lutOther) })
+	})
+}
@@ -60,6 +60,62 @@ func (e *Encoder[T]) DecodeLWECustom(pt LWEPlaintext[T], messageModulus, scale T
 	return int(decoded % messageModulus)
 }
 
+// EncodeLWESigned encodes signed integer message to LWE plaintext,
+// using two's complement over [-MessageModulus/2, MessageModulus/2).
+// Parameter's MessageModulus and Scale are used.
+func (e *Encoder[T]) EncodeLWESigned(message int) LWEPlaintext[T] {
+	return e.EncodeLWESignedCustom(message, e.Parameters.messageModulus, e.Parameters.scale)
+}
+
+// EncodeLWESignedCustom encodes signed integer message to LWE plaintext
+// using custom MessageModulus and Scale,
+// using two's complement over [-messageModulus/2, messageModulus/2).
+//
+// If MessageModulus = 0, then no modulus reduction is performed.
+func (e *Encoder[T]) EncodeLWESignedCustom(message int, messageModulus, scale T) LWEPlaintext[T] {
+	if messageModulus == 0 {
+		return LWEPlaintext[T]{Value: T(message) * scale}
+	}
+	return LWEPlaintext[T]{Value: T(toUnsignedMessage(message, int(messageModulus))) * scale}
+}
+
+// DecodeLWESigned decodes LWE plaintext to signed integer message
+// in [-MessageModulus/2, MessageModulus/2).
+// Parameter's MessageModulus and Scale are used.
+func (e *Encoder[T]) DecodeLWESigned(pt LWEPlaintext[T]) int {
+	return e.DecodeLWESignedCustom(pt, e.Parameters.messageModulus, e.Parameters.scale)
+}
+
+// DecodeLWESignedCustom decodes LWE plaintext to signed integer message
+// in [-messageModulus/2, messageModulus/2)
+// using custom MessageModulus and Scale.
+//
+// If MessageModulus = 0, then it is automatically set to round(Q / scale).
+func (e *Encoder[T]) DecodeLWESignedCustom(pt LWEPlaintext[T], messageModulus, scale T) int {
+	if messageModulus == 0 {
+		messageModulus = T(math.Round(math.Exp2(float64(e.Parameters.logQ)) / float64(scale)))
+	}
+	return toSignedMessage(e.DecodeLWECustom(pt, messageModulus, scale), int(messageModulus))
+}
+
+// toSignedMessage converts message in [0, messageModulus)
+// to its two's complement representative in [-messageModulus/2, messageModulus/2).
+func toSignedMessage(message, messageModulus int) int {
+	if message >= messageModulus-messageModulus/2 {
+		return message - messageModulus
+	}
+	return message
+}
+
+// toUnsignedMessage reduces message to [0, messageModulus).
+func toUnsignedMessage(message, messageModulus int) int {
+	message %= messageModulus
+	if message < 0 {
+		message += messageModulus
+	}
+	return message
+}
+
 // EncodeGLWE encodes up to Parameters.PolyDegree integer messages into one GLWE plaintext.
 // Parameter's MessageModulus and Scale are used.
 //
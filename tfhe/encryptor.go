@@ -1,8 +1,6 @@
 package tfhe
 
 import (
-	"math/bits"
-
 	"github.com/sp301415/tfhe-go/math/csprng"
 	"github.com/sp301415/tfhe-go/math/poly"
 	"github.com/sp301415/tfhe-go/math/vec"
@@ -76,49 +74,50 @@ func NewEncryptor[T TorusInt](params Parameters[T]) *Encryptor[T] {
 	return &encryptor
 }
 
+// NewEncryptorHierarchyWithSharedLWEKey returns a initialized Encryptor for each depth of the hierarchy.
+// The returned slice has length HierarchyDepth, and its d-th element uses parameters params.AtDepth(d+1).
+// Every LWELargeKey is a prefix of the first one, so all Encryptors share the same LWEKey.
+// It also automatically samples the keys, using [GenHierarchicalSecretKey].
 func NewEncryptorHierarchyWithSharedLWEKey[T TorusInt](params Parameters[T]) []*Encryptor[T] {
-	depth := bits.TrailingZeros(uint(params.polyDegree / 2048))
-	encryptors := make([]*Encryptor[T], depth)
-
-	polyDegree := params.polyDegree / 2
-	lookupTableSize := params.lookUpTableSize / 2
-	glweDimension := params.glweDimension / 2
-	logPolyDegree := params.logPolyDegree - 1
-	for i := 0; i < depth; i++ {
-		// Parameter 복사해서 polyDegree, lookupTableSize 수정
-		newParams := params
-		newParams.polyDegree = polyDegree
-		newParams.lookUpTableSize = lookupTableSize
-		newParams.glweDimension = glweDimension
-		newParams.logPolyDegree = logPolyDegree
-		// Encryptor 생성
-		encryptors[i] = NewEncryptor(newParams)
-
-		// 다음 레벨: 절반씩 줄이기
-		polyDegree /= 2
-		lookupTableSize /= 2
-		glweDimension /= 2
-		logPolyDegree--
-	}
-	encryptors[0].SecretKey = encryptors[0].GenSecretKey()
-
-	for i := 1; i < depth; i++ {
-		length := encryptors[i].Parameters.glweDimension
-
-		encryptors[i].SecretKey.LWELargeKey.Value = encryptors[0].SecretKey.LWELargeKey.Value[0:length]
-
-		encryptors[i].SecretKey.LWEKey.Value = encryptors[i].SecretKey.LWELargeKey.Value[:encryptors[i].Parameters.lweDimension]
+	return NewEncryptorHierarchyWithKey(GenHierarchicalSecretKey(params))
+}
 
-		glweRank := encryptors[i].Parameters.glweRank
-		polyDegree := encryptors[i].Parameters.polyDegree
-		for j := 0; j < glweRank; j++ {
-			encryptors[i].SecretKey.GLWEKey.Value[j].Coeffs = encryptors[i].SecretKey.LWELargeKey.Value[j*polyDegree : (j+1)*polyDegree]
+// NewEncryptorHierarchyWithKey returns a initialized Encryptor for each depth of the hierarchy with given key.
+// The d-th Encryptor uses parameters sk.Parameters.AtDepth(d+1) and key sk.Value[d].
+// This does not copy secret keys.
+//
+// Panics when the key of some depth is not a prefix of the root key.
+func NewEncryptorHierarchyWithKey[T TorusInt](sk HierarchicalSecretKey[T]) []*Encryptor[T] {
+	encryptors := make([]*Encryptor[T], sk.Depth())
+	for i := range encryptors {
+		if !sk.IsPrefix(i) {
+			panic("SecretKey not prefix of root key")
 		}
+		encryptors[i] = NewEncryptorWithKey(sk.Parameters.AtDepth(i+1), sk.Value[i])
+	}
+	return encryptors
+}
 
-		encryptors[i].ToFourierGLWESecretKeyAssign(encryptors[i].SecretKey.GLWEKey, encryptors[i].SecretKey.FourierGLWEKey)
+// GenHierarchicalSecretKey samples a new HierarchicalSecretKey.
+// The root key is sampled as in [*Encryptor.GenSecretKey] with parameters params.AtDepth(1).
+//
+// Panics when HierarchyDepth is smaller than one.
+func GenHierarchicalSecretKey[T TorusInt](params Parameters[T]) HierarchicalSecretKey[T] {
+	sk := NewHierarchicalSecretKey(params)
+
+	rootKey := sk.Value[0]
+	lweDimension := len(rootKey.LWEKey.Value)
+	s := csprng.NewBinarySampler[T]()
+	if params.blockSize == 1 {
+		s.SampleVecAssign(rootKey.LWELargeKey.Value)
+	} else {
+		s.SampleBlockVecAssign(params.blockSize, rootKey.LWELargeKey.Value[:lweDimension])
+		s.SampleVecAssign(rootKey.LWELargeKey.Value[lweDimension:])
 	}
 
-	return encryptors
+	sk.updateFourierGLWEKeys()
+
+	return sk
 }
 
 // NewEncryptorWithKey returns a initialized Encryptor with given parameters and key.
@@ -101,6 +101,130 @@ func (sk *SecretKey[T]) Clear() {
 	sk.FourierGLWEKey.Clear()
 }
 
+// HierarchicalSecretKey is a secret key for the evaluator hierarchy,
+// which consists of SecretKeys for each depth.
+// All keys should be treated as read-only.
+// Changing them mid-operation will usually result in wrong results.
+//
+// Every SecretKey shares the backing slice of the root key Value[0].LWELargeKey,
+// so the LWELargeKey of each depth is a prefix of the root key.
+// Only the root key is serialized, and the other keys are reconstructed on load.
+type HierarchicalSecretKey[T TorusInt] struct {
+	// Parameters is the parameters of depth zero, which the hierarchy is derived from.
+	Parameters Parameters[T]
+	// Value has length HierarchyDepth.
+	// Value[d] is the SecretKey for parameters Parameters.AtDepth(d+1).
+	Value []SecretKey[T]
+}
+
+// NewHierarchicalSecretKey creates a new HierarchicalSecretKey.
+// Each key shares the same backing slice, held by Value[0].LWELargeKey.
+//
+// Panics when HierarchyDepth is smaller than one.
+func NewHierarchicalSecretKey[T TorusInt](params Parameters[T]) HierarchicalSecretKey[T] {
+	if params.hierarchyDepth < 1 {
+		panic("HierarchyDepth smaller than one")
+	}
+
+	lweLargeKey := LWESecretKey[T]{Value: make([]T, params.AtDepth(1).glweDimension)}
+	return newHierarchicalSecretKeyFromRoot(params, lweLargeKey)
+}
+
+// newHierarchicalSecretKeyFromRoot creates a new HierarchicalSecretKey
+// whose keys are views of the root key lweLargeKey.
+// FourierGLWEKeys are allocated, but not computed.
+func newHierarchicalSecretKeyFromRoot[T TorusInt](params Parameters[T], lweLargeKey LWESecretKey[T]) HierarchicalSecretKey[T] {
+	sks := make([]SecretKey[T], params.hierarchyDepth)
+	for i := range sks {
+		paramsDepth := params.AtDepth(i + 1)
+
+		lweLargeKeyDepth := LWESecretKey[T]{Value: lweLargeKey.Value[:paramsDepth.glweDimension]}
+
+		glweKey := GLWESecretKey[T]{Value: make([]poly.Poly[T], paramsDepth.glweRank)}
+		for j := 0; j < paramsDepth.glweRank; j++ {
+			glweKey.Value[j].Coeffs = lweLargeKeyDepth.Value[j*paramsDepth.polyDegree : (j+1)*paramsDepth.polyDegree]
+		}
+
+		sks[i] = SecretKey[T]{
+			LWELargeKey:    lweLargeKeyDepth,
+			GLWEKey:        glweKey,
+			FourierGLWEKey: NewFourierGLWESecretKey(paramsDepth),
+			LWEKey:         LWESecretKey[T]{Value: lweLargeKeyDepth.Value[:paramsDepth.lweDimension]},
+		}
+	}
+
+	return HierarchicalSecretKey[T]{Parameters: params, Value: sks}
+}
+
+// updateFourierGLWEKeys recomputes FourierGLWEKey of each depth from the root key.
+func (sk HierarchicalSecretKey[T]) updateFourierGLWEKeys() {
+	for i := range sk.Value {
+		polyDegree := sk.Value[i].GLWEKey.Value[0].Degree()
+		NewGLWETransformer[T](polyDegree).ToFourierGLWESecretKeyAssign(sk.Value[i].GLWEKey, sk.Value[i].FourierGLWEKey)
+	}
+}
+
+// Depth returns the number of depths of this key.
+func (sk HierarchicalSecretKey[T]) Depth() int {
+	return len(sk.Value)
+}
+
+// IsPrefix returns true if the key of given depth is a prefix of the root key,
+// that is, if its LWELargeKey, LWEKey and GLWEKey agree with the first elements of Value[0].LWELargeKey.
+func (sk HierarchicalSecretKey[T]) IsPrefix(depth int) bool {
+	root := sk.Value[0].LWELargeKey.Value
+	skDepth := sk.Value[depth]
+
+	lweLargeKey := skDepth.LWELargeKey.Value
+	if len(lweLargeKey) > len(root) || !vec.Equals(lweLargeKey, root[:len(lweLargeKey)]) {
+		return false
+	}
+
+	lweKey := skDepth.LWEKey.Value
+	if len(lweKey) > len(lweLargeKey) || !vec.Equals(lweKey, lweLargeKey[:len(lweKey)]) {
+		return false
+	}
+
+	polyDegree := skDepth.GLWEKey.Value[0].Degree()
+	if len(skDepth.GLWEKey.Value)*polyDegree != len(lweLargeKey) {
+		return false
+	}
+	for i := range skDepth.GLWEKey.Value {
+		if !vec.Equals(skDepth.GLWEKey.Value[i].Coeffs, lweLargeKey[i*polyDegree:(i+1)*polyDegree]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy returns a copy of the key.
+// The copied keys share a new backing slice, so the prefix structure is preserved.
+func (sk HierarchicalSecretKey[T]) Copy() HierarchicalSecretKey[T] {
+	skCopy := newHierarchicalSecretKeyFromRoot(sk.Parameters, sk.Value[0].LWELargeKey.Copy())
+	for i := range skCopy.Value {
+		skCopy.Value[i].FourierGLWEKey.CopyFrom(sk.Value[i].FourierGLWEKey)
+	}
+	return skCopy
+}
+
+// CopyFrom copies values from the key.
+func (sk *HierarchicalSecretKey[T]) CopyFrom(skIn HierarchicalSecretKey[T]) {
+	vec.CopyAssign(skIn.Value[0].LWELargeKey.Value, sk.Value[0].LWELargeKey.Value)
+	for i := range sk.Value {
+		sk.Value[i].FourierGLWEKey.CopyFrom(skIn.Value[i].FourierGLWEKey)
+	}
+	sk.Parameters = skIn.Parameters
+}
+
+// Clear clears the key.
+func (sk *HierarchicalSecretKey[T]) Clear() {
+	vec.Fill(sk.Value[0].LWELargeKey.Value, 0)
+	for i := range sk.Value {
+		sk.Value[i].FourierGLWEKey.Clear()
+	}
+}
+
 // PublicKey is a structure containing LWE and GLWE public key.
 // All keys should be treated as read-only.
 // Changing them mid-operation will usually result in wrong computation.
@@ -3,6 +3,7 @@ package tfhe
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
 
 	"github.com/sp301415/tfhe-go/math/num"
@@ -143,6 +144,100 @@ func (sk *SecretKey[T]) UnmarshalBinary(data []byte) error {
 	return err
 }
 
+// ByteSize returns the size of the key in bytes.
+func (sk HierarchicalSecretKey[T]) ByteSize() int {
+	return sk.Parameters.ByteSize() + 8 + sk.Value[0].LWELargeKey.ByteSize()
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+// Only the root key Value[0].LWELargeKey is written.
+//
+// The encoded form is as follows:
+//
+//	    Parameters
+//	[8] Depth
+//	    LWELargeKey
+func (sk HierarchicalSecretKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	if nWrite64, err = sk.Parameters.WriteTo(w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	depth := len(sk.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(depth))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	if nWrite64, err = sk.Value[0].LWELargeKey.WriteTo(w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	if n < int64(sk.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+// Keys of each depth are reconstructed as prefixes of the root key,
+// and FourierGLWEKeys are recomputed.
+func (sk *HierarchicalSecretKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	var params Parameters[T]
+	if nRead64, err = params.ReadFrom(r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	depth := int(binary.BigEndian.Uint64(buf[:]))
+	if depth < 1 || depth != params.hierarchyDepth {
+		return n, errors.New("Depth not equal to HierarchyDepth")
+	}
+
+	var lweLargeKey LWESecretKey[T]
+	if nRead64, err = lweLargeKey.ReadFrom(r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+	if len(lweLargeKey.Value) != params.AtDepth(1).glweDimension {
+		return n, errors.New("LWELargeKey length not equal to GLWEDimension")
+	}
+
+	*sk = newHierarchicalSecretKeyFromRoot(params, lweLargeKey)
+	sk.updateFourierGLWEKeys()
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (sk HierarchicalSecretKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, sk.ByteSize()))
+	_, err = sk.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (sk *HierarchicalSecretKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := sk.ReadFrom(buf)
+	return err
+}
+
 // ByteSize returns the size of the key in bytes.
 func (pk PublicKey[T]) ByteSize() int {
 	return pk.LWEKey.ByteSize() + pk.GLWEKey.ByteSize()
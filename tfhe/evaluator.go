@@ -3,6 +3,7 @@ package tfhe
 import (
 	"math"
 
+	"github.com/sp301415/tfhe-go/math/num"
 	"github.com/sp301415/tfhe-go/math/poly"
 )
 
@@ -31,6 +32,11 @@ type Evaluator[T TorusInt] struct {
 	// modSwitchConstant is a constant for modulus switching.
 	modSwitchConstant float64
 
+	// fullDomainBranches are the evaluators for each independent blind rotation
+	// in extended full-domain bootstrapping.
+	// This is nil unless enabled by [*Evaluator.SetConcurrentFullDomain].
+	fullDomainBranches []*Evaluator[T]
+
 	buffer evaluationBuffer[T]
 }
 
@@ -43,6 +49,18 @@ type evaluationBuffer[T TorusInt] struct {
 	ctProdLWE LWECiphertext[T]
 	// ctProdFourierGLWE is the fourier transformed ctGLWEOut in ExternalProductGLWE and KeySwitchGLWE.
 	ctProdFourierGLWE FourierGLWECiphertext[T]
+	// ctPermute is the permuted GLWE ciphertext in AutomorphismGLWE.
+	// This is allocated on first use.
+	ctPermute GLWECiphertext[T]
+	// ctPackOdd is the packed odd-indexed ciphertexts for each level in PackLWEToGLWETrace.
+	// This has length log(PolyDegree), and is allocated on first use.
+	ctPackOdd []GLWECiphertext[T]
+	// ctPackMono is X^(N/2^l) times the packed odd-indexed ciphertexts in PackLWEToGLWETrace.
+	// This is allocated on first use.
+	ctPackMono GLWECiphertext[T]
+	// pPack is the i-th entries of the masks of LWE ciphertexts packed to a polynomial in PackLWEToGLWE.
+	// This is allocated on first use.
+	pPack poly.Poly[T]
 	// ctCMux is ct1 - ct0 in CMux.
 	ctCMux GLWECiphertext[T]
 
@@ -68,14 +86,20 @@ type evaluationBuffer[T TorusInt] struct {
 	ctExtract LWECiphertext[T]
 	// ctKeySwitchForBootstrap is the LWEDimension sized ciphertext from keyswitching for bootstrapping.
 	ctKeySwitchForBootstrap LWECiphertext[T]
+	// ctPackedKeySwitch is the input LWE ciphertext packed to GLWE ciphertext in KeySwitchForBootstrap.
+	// This is only used with KeySwitchMethodGLWE, and is allocated on first use.
+	ctPackedKeySwitch GLWECiphertext[T]
+	// ctPackedKeySwitchOut is the output of the keyswitching of ctPackedKeySwitch.
+	// This is only used with KeySwitchMethodGLWE, and is allocated on first use.
+	ctPackedKeySwitchOut GLWECiphertext[T]
 
 	ctEBSAcc          GLWECiphertext[T]
 	ctKeySwitchForEBS LWECiphertext[T]
 	ctLWEExtracted    LWECiphertext[T]
 	// lut is an empty lut, used for BlindRotateFunc.
 	lut LookUpTable[T]
-	// lutRaw is an full-sized LUT.
-	lutRaw []T
+	// lutBuilder is a LookUpTableBuilder for generating LUTs.
+	lutBuilder *LookUpTableBuilder[T]
 }
 
 // NewEvaluator creates a new Evaluator based on parameters.
@@ -103,37 +127,22 @@ func NewEvaluator[T TorusInt](params Parameters[T], evk EvaluationKey[T]) *Evalu
 	}
 }
 
+// NewEvaluatorHierarchy creates a new Evaluator for the given depth of the hierarchy,
+// based on parameters params.AtDepth(depth).
+// This does not copy evaluation keys, since they may be large.
 func NewEvaluatorHierarchy[T TorusInt](params Parameters[T], evk EvaluationKey[T], depth int) *Evaluator[T] {
-	polyDegree := params.polyDegree / int(math.Pow(2, float64(depth)))
-	decomposer := NewDecomposer[T](polyDegree)
-	decomposer.ScalarDecomposedBuffer(params.keySwitchParameters)
-	decomposer.PolyDecomposedBuffer(params.blindRotateParameters)
-	decomposer.PolyFourierDecomposedBuffer(params.blindRotateParameters)
-
-	lookupTableSize := params.lookUpTableSize / int(math.Pow(2, float64(depth)))
-	glweDimension := params.glweDimension / int(math.Pow(2, float64(depth)))
-	logPolyDegree := params.logPolyDegree - depth
-
-	newParams := params
-	newParams.polyDegree = polyDegree
-	newParams.lookUpTableSize = lookupTableSize
-	newParams.glweDimension = glweDimension
-	newParams.logPolyDegree = logPolyDegree
-	return &Evaluator[T]{
-		Encoder:         NewEncoder(newParams),
-		GLWETransformer: NewGLWETransformer[T](newParams.polyDegree),
-
-		Parameters: newParams,
-
-		Decomposer:    decomposer,
-		PolyEvaluator: poly.NewEvaluator[T](newParams.polyDegree),
-
-		EvaluationKey: evk,
-
-		modSwitchConstant: float64(newParams.lookUpTableSize) / math.Exp2(float64(newParams.logQ)),
+	return NewEvaluator(params.AtDepth(depth), evk)
+}
 
-		buffer: newEvaluationBuffer(newParams),
+// NewEvaluatorHierarchyFromKey creates a new Evaluator for each depth of the hierarchy.
+// The returned slice has length HierarchyDepth, and its d-th element is for depth d.
+// This does not copy evaluation keys, since they may be large.
+func NewEvaluatorHierarchyFromKey[T TorusInt](evk HierarchicalEvaluationKey[T]) []*Evaluator[T] {
+	evaluators := make([]*Evaluator[T], len(evk.Value))
+	for i := range evk.Value {
+		evaluators[i] = NewEvaluatorHierarchy(evk.Parameters, evk.Value[i], i+1)
 	}
+	return evaluators
 }
 
 // newEvaluationBuffer creates a new evaluationBuffer.
@@ -181,14 +190,14 @@ func newEvaluationBuffer[T TorusInt](params Parameters[T]) evaluationBuffer[T] {
 		ctKeySwitchForEBS:       ctKeySwitchForEBS,
 		ctLWEExtracted:          ctLWEExtracted,
 		lut:                     NewLookUpTable(params),
-		lutRaw:                  make([]T, params.lookUpTableSize),
+		lutBuilder:              NewLookUpTableBuilder[T](params.polyExtendFactor, params.polyDegree, NegacyclicPadding, OffsetCellStart),
 	}
 }
 
 // ShallowCopy returns a shallow copy of this Evaluator.
 // Returned Evaluator is safe for concurrent use.
 func (e *Evaluator[T]) ShallowCopy() *Evaluator[T] {
-	return &Evaluator[T]{
+	eval := &Evaluator[T]{
 		Encoder:         e.Encoder,
 		GLWETransformer: e.GLWETransformer.ShallowCopy(),
 
@@ -203,6 +212,29 @@ func (e *Evaluator[T]) ShallowCopy() *Evaluator[T] {
 
 		buffer: newEvaluationBuffer(e.Parameters),
 	}
+	eval.SetConcurrentFullDomain(e.fullDomainBranches != nil)
+
+	return eval
+}
+
+// SetConcurrentFullDomain sets whether extended full-domain bootstrapping
+// runs its independent blind rotations concurrently.
+//
+// If concurrent is true, each NegLUT and the compress bootstrapping is blind rotated
+// in its own goroutine with its own buffers, and the results are summed at the end.
+// This reduces the latency of a single bootstrapping on multicore machines,
+// at the cost of additional memory for the buffers.
+func (e *Evaluator[T]) SetConcurrentFullDomain(concurrent bool) {
+	e.fullDomainBranches = nil
+	if !concurrent {
+		return
+	}
+
+	branches := make([]*Evaluator[T], num.Log2(e.Parameters.baseExtendFactor)+1)
+	for i := range branches {
+		branches[i] = e.ShallowCopy()
+	}
+	e.fullDomainBranches = branches
 }
 
 func (e *Evaluator[T]) ModSwitchConstant() float64 {
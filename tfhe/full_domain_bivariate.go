@@ -0,0 +1,111 @@
+package tfhe
+
+// GenDecomposedLUTBivariate generates a decomposed LUT for bivariate function f,
+// where the second input is in [0, messageModulus1).
+// Output of f is cut by MessageModulus.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTBivariate(f func(x, y int) int, messageModulus1 int) DecomposedLookUpTable[T] {
+	lutOut := e.NewDecomposedLUT()
+	e.GenDecomposedLUTBivariateAssign(f, messageModulus1, &lutOut)
+	return lutOut
+}
+
+// GenDecomposedLUTBivariateAssign generates a decomposed LUT for bivariate function f
+// and writes it to lutOut, where the second input is in [0, messageModulus1).
+// Output of f is cut by MessageModulus.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTBivariateAssign(f func(x, y int) int, messageModulus1 int, lutOut *DecomposedLookUpTable[T]) {
+	checkBivariateMessageModulus(messageModulus1, e.Parameters)
+	e.GenDecomposedLUTAssign(func(x int) int { return f(x/messageModulus1, x%messageModulus1) }, lutOut)
+}
+
+// checkBivariateMessageModulus panics when messageModulus1 is not in [1, MessageModulus].
+func checkBivariateMessageModulus[T TorusInt](messageModulus1 int, params Parameters[T]) {
+	switch {
+	case messageModulus1 < 1:
+		panic("MessageModulus1 smaller than one")
+	case messageModulus1 > int(params.messageModulus):
+		panic("MessageModulus1 larger than MessageModulus")
+	}
+}
+
+// BootstrapBivariate returns a bootstrapped LWE ciphertext of f(x, y),
+// where ct0 encrypts x in [0, MessageModulus / BivariateMessageModulus)
+// and ct1 encrypts y in [0, BivariateMessageModulus).
+// Output of f is cut by MessageModulus.
+//
+// ct0 is multiplied by BivariateMessageModulus before bootstrapping, which amplifies the error,
+// so [Parameters.BivariateMessageModulus] is chosen to keep the failure probability small.
+// For example, it is 4 for Params5 and Params7, and 8 for Params6.
+// Use [*FullDomainEvaluator.BootstrapBivariateCustom] to use another split of the message space.
+//
+// Panics when BivariateMessageModulus is zero, as in Params8.
+func (e *FullDomainEvaluator[T]) BootstrapBivariate(ct0, ct1 LWECiphertext[T], f func(x, y int) int) LWECiphertext[T] {
+	ctOut := NewLWECiphertext(e.Parameters)
+	e.BootstrapBivariateAssign(ct0, ct1, f, ctOut)
+	return ctOut
+}
+
+// BootstrapBivariateAssign bootstraps LWE ciphertexts of x and y with respect to f(x, y) and writes it to ctOut,
+// where ct0 encrypts x in [0, MessageModulus / BivariateMessageModulus)
+// and ct1 encrypts y in [0, BivariateMessageModulus).
+// Output of f is cut by MessageModulus.
+//
+// Panics when BivariateMessageModulus is zero, as in Params8.
+func (e *FullDomainEvaluator[T]) BootstrapBivariateAssign(ct0, ct1 LWECiphertext[T], f func(x, y int) int, ctOut LWECiphertext[T]) {
+	messageModulus1 := int(e.Parameters.BivariateMessageModulus())
+	if messageModulus1 == 0 {
+		panic("BivariateMessageModulus zero")
+	}
+	e.BootstrapBivariateCustomAssign(ct0, ct1, f, messageModulus1, ctOut)
+}
+
+// BootstrapBivariateCustom returns a bootstrapped LWE ciphertext of f(x, y),
+// where ct0 encrypts x in [0, MessageModulus / messageModulus1)
+// and ct1 encrypts y in [0, messageModulus1).
+// Output of f is cut by MessageModulus.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) BootstrapBivariateCustom(ct0, ct1 LWECiphertext[T], f func(x, y int) int, messageModulus1 int) LWECiphertext[T] {
+	ctOut := NewLWECiphertext(e.Parameters)
+	e.BootstrapBivariateCustomAssign(ct0, ct1, f, messageModulus1, ctOut)
+	return ctOut
+}
+
+// BootstrapBivariateCustomAssign bootstraps LWE ciphertexts of x and y with respect to f(x, y) and writes it to ctOut,
+// where ct0 encrypts x in [0, MessageModulus / messageModulus1)
+// and ct1 encrypts y in [0, messageModulus1).
+// Output of f is cut by MessageModulus.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) BootstrapBivariateCustomAssign(ct0, ct1 LWECiphertext[T], f func(x, y int) int, messageModulus1 int, ctOut LWECiphertext[T]) {
+	e.GenDecomposedLUTBivariateAssign(f, messageModulus1, &e.buffer.lut)
+	e.BootstrapBivariateLUTAssign(ct0, ct1, e.buffer.lut, messageModulus1, ctOut)
+}
+
+// BootstrapBivariateLUT returns a bootstrapped LWE ciphertext with respect to given bivariate decomposed LUT,
+// generated by [*FullDomainEvaluator.GenDecomposedLUTBivariate] with messageModulus1.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) BootstrapBivariateLUT(ct0, ct1 LWECiphertext[T], lut DecomposedLookUpTable[T], messageModulus1 int) LWECiphertext[T] {
+	ctOut := NewLWECiphertext(e.Parameters)
+	e.BootstrapBivariateLUTAssign(ct0, ct1, lut, messageModulus1, ctOut)
+	return ctOut
+}
+
+// BootstrapBivariateLUTAssign bootstraps LWE ciphertexts with respect to given bivariate decomposed LUT and writes it to ctOut.
+// lut should be generated by [*FullDomainEvaluator.GenDecomposedLUTBivariate] with messageModulus1.
+//
+// The inputs are packed as messageModulus1 * ct0 + ct1,
+// which does not need a padding bit since the decomposed LUT covers the full message space.
+//
+// Panics when messageModulus1 is smaller than one or larger than MessageModulus.
+func (e *FullDomainEvaluator[T]) BootstrapBivariateLUTAssign(ct0, ct1 LWECiphertext[T], lut DecomposedLookUpTable[T], messageModulus1 int, ctOut LWECiphertext[T]) {
+	checkBivariateMessageModulus(messageModulus1, e.Parameters)
+
+	e.Evaluators[0].ScalarMulLWEAssign(ct0, T(messageModulus1), e.buffer.ctBivariate)
+	e.Evaluators[0].AddLWEAssign(e.buffer.ctBivariate, ct1, e.buffer.ctBivariate)
+	e.BootstrapLUTAssign(e.buffer.ctBivariate, lut, ctOut)
+}
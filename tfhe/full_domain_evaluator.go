@@ -0,0 +1,387 @@
+package tfhe
+
+import (
+	"math"
+	"sync"
+)
+
+// FullDomainEvaluator evaluates full-domain functional bootstrapping
+// using recursive LUT decomposition over the evaluator hierarchy.
+// This is meant to be public, usually for servers.
+//
+// Input and output ciphertexts are encrypted under the LWE key of the hierarchy,
+// which is shared by every depth. Every function is evaluated over the full message space.
+//
+// FullDomainEvaluator is not safe for concurrent use.
+// Use [*FullDomainEvaluator.ShallowCopy] to get a safe copy.
+type FullDomainEvaluator[T TorusInt] struct {
+	// Encoder is an embedded encoder for this FullDomainEvaluator.
+	*Encoder[T]
+
+	// Parameters is the parameters for this FullDomainEvaluator.
+	Parameters Parameters[T]
+
+	// Evaluators are the evaluators for each depth of the hierarchy.
+	// Evaluators[d] uses parameters Parameters.AtDepth(d+1),
+	// so the last evaluator works over the base ring.
+	Evaluators []*Evaluator[T]
+
+	// lutEvaluator is a keyless evaluator for generating decomposed LUTs.
+	lutEvaluator *Evaluator[T]
+
+	// compressLUT is the LUT for compressing the input to the base ring.
+	compressLUT LookUpTable[T]
+	// modSwitchConstant is a constant for modulus switching.
+	modSwitchConstant float64
+
+	// mulLUTs are the LUTs used by [*FullDomainEvaluator.MulLWE].
+	// This is nil until MulLWE is first called.
+	mulLUTs []DecomposedLookUpTable[T]
+
+	// compressEvaluator is a copy of the last evaluator for the compress bootstrapping,
+	// so that it can run concurrently with the NegLUT bootstrappings.
+	// This is nil unless enabled by [*FullDomainEvaluator.SetConcurrent].
+	compressEvaluator *Evaluator[T]
+
+	buffer fullDomainEvaluationBuffer[T]
+}
+
+// fullDomainEvaluationBuffer is a buffer for FullDomainEvaluator.
+type fullDomainEvaluationBuffer[T TorusInt] struct {
+	// ctBootstrap is the output of a single bootstrapping in the hierarchy.
+	ctBootstrap LWECiphertext[T]
+	// ctCompress is the input compressed to the base ring.
+	ctCompress LWECiphertext[T]
+	// ctAcc is the accumulated output of bootstrappings.
+	ctAcc LWECiphertext[T]
+	// ctBranch is the output of each concurrent bootstrapping.
+	// This has length HierarchyDepth + 1, where the last element is for the BaseLUT.
+	ctBranch []LWECiphertext[T]
+	// ctBivariate is the packed input of bivariate bootstrapping.
+	ctBivariate LWECiphertext[T]
+	// ctMul holds the intermediate values of multiplication.
+	ctMul [4]LWECiphertext[T]
+
+	// lut is an empty decomposed LUT, used for BootstrapFunc.
+	lut DecomposedLookUpTable[T]
+}
+
+// NewFullDomainEvaluator creates a new FullDomainEvaluator based on parameters.
+// evk should be generated by [GenHierarchicalEvaluationKeyParallel] with params.
+// This does not copy evaluation keys, since they may be large.
+//
+// Panics when parameters do not support the hierarchical pipeline,
+// or when evk was not generated for params.
+func NewFullDomainEvaluator[T TorusInt](params Parameters[T], evk HierarchicalEvaluationKey[T]) *FullDomainEvaluator[T] {
+	switch {
+	case params.bootstrapOrder != OrderBlindRotateKeySwitch:
+		panic("BootstrapOrder not OrderBlindRotateKeySwitch")
+	case params.lookUpTableSize != params.polyDegree:
+		panic("LookUpTableSize not equal to PolyDegree")
+	case params.hierarchyDepth < 1:
+		panic("HierarchyDepth smaller than one")
+	case evk.Parameters != params:
+		panic("EvaluationKey generated for different parameters")
+	case evk.Depth() != params.hierarchyDepth:
+		panic("EvaluationKey depth not equal to HierarchyDepth")
+	}
+
+	evaluators := NewEvaluatorHierarchyFromKey(evk)
+
+	lutEvaluator := NewEvaluator(params, EvaluationKey[T]{})
+	compressLUT := NewLookUpTableCustom[T](1, params.basePolyDegree)
+	lutEvaluator.GenCompressLUTAssign(compressLUT)
+
+	return &FullDomainEvaluator[T]{
+		Encoder: NewEncoder(params),
+
+		Parameters: params,
+
+		Evaluators: evaluators,
+
+		lutEvaluator: lutEvaluator,
+
+		compressLUT:       compressLUT,
+		modSwitchConstant: float64(params.lookUpTableSize) / math.Exp2(float64(params.logQ)),
+
+		buffer: newFullDomainEvaluationBuffer(params),
+	}
+}
+
+// newFullDomainEvaluationBuffer creates a new fullDomainEvaluationBuffer.
+func newFullDomainEvaluationBuffer[T TorusInt](params Parameters[T]) fullDomainEvaluationBuffer[T] {
+	ctBranch := make([]LWECiphertext[T], params.hierarchyDepth+1)
+	for i := range ctBranch {
+		ctBranch[i] = NewLWECiphertext(params)
+	}
+
+	var ctMul [4]LWECiphertext[T]
+	for i := range ctMul {
+		ctMul[i] = NewLWECiphertext(params)
+	}
+
+	return fullDomainEvaluationBuffer[T]{
+		ctBootstrap: NewLWECiphertext(params),
+		ctCompress:  NewLWECiphertext(params),
+		ctAcc:       NewLWECiphertext(params),
+		ctBranch:    ctBranch,
+		ctBivariate: NewLWECiphertext(params),
+		ctMul:       ctMul,
+
+		lut: NewDecomposedLookUpTable(params),
+	}
+}
+
+// ShallowCopy returns a shallow copy of this FullDomainEvaluator.
+// Returned FullDomainEvaluator is safe for concurrent use.
+func (e *FullDomainEvaluator[T]) ShallowCopy() *FullDomainEvaluator[T] {
+	evaluators := make([]*Evaluator[T], len(e.Evaluators))
+	for i := range evaluators {
+		evaluators[i] = e.Evaluators[i].ShallowCopy()
+	}
+
+	eval := &FullDomainEvaluator[T]{
+		Encoder: e.Encoder,
+
+		Parameters: e.Parameters,
+
+		Evaluators: evaluators,
+
+		lutEvaluator: e.lutEvaluator.ShallowCopy(),
+
+		compressLUT:       e.compressLUT,
+		modSwitchConstant: e.modSwitchConstant,
+
+		mulLUTs: e.mulLUTs,
+
+		buffer: newFullDomainEvaluationBuffer(e.Parameters),
+	}
+	eval.SetConcurrent(e.compressEvaluator != nil)
+
+	return eval
+}
+
+// SetConcurrent sets whether bootstrapping runs its independent blind rotations concurrently.
+//
+// If concurrent is true, each NegLUT bootstrapping and the compress bootstrapping
+// runs in its own goroutine with its own buffers, and the results are summed at the end.
+// This reduces the latency of a single bootstrapping on multicore machines,
+// at the cost of additional memory for the buffers.
+// Multi-value bootstrapping always runs sequentially.
+func (e *FullDomainEvaluator[T]) SetConcurrent(concurrent bool) {
+	e.compressEvaluator = nil
+	if concurrent {
+		e.compressEvaluator = e.Evaluators[len(e.Evaluators)-1].ShallowCopy()
+	}
+}
+
+// NewDecomposedLUT allocates an empty decomposed LUT for this FullDomainEvaluator.
+func (e *FullDomainEvaluator[T]) NewDecomposedLUT() DecomposedLookUpTable[T] {
+	return NewDecomposedLookUpTable(e.Parameters)
+}
+
+// GenDecomposedLUT generates a decomposed LUT based on function f.
+// Input and output of f is cut by MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUT(f func(int) int) DecomposedLookUpTable[T] {
+	lutOut := e.NewDecomposedLUT()
+	e.GenDecomposedLUTAssign(f, &lutOut)
+	return lutOut
+}
+
+// GenDecomposedLUTAssign generates a decomposed LUT based on function f and writes it to lutOut.
+// Input and output of f is cut by MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTAssign(f func(int) int, lutOut *DecomposedLookUpTable[T]) {
+	e.lutEvaluator.GenLookUpTableNegDecomposedAssign(f, e.Parameters.messageModulus, e.Parameters.scale, lutOut)
+}
+
+// GenDecomposedLUTSigned generates a decomposed LUT based on function f over signed messages.
+// Input and output of f is in [-MessageModulus/2, MessageModulus/2), using two's complement.
+// Output of f is cut by MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTSigned(f func(int) int) DecomposedLookUpTable[T] {
+	lutOut := e.NewDecomposedLUT()
+	e.GenDecomposedLUTSignedAssign(f, &lutOut)
+	return lutOut
+}
+
+// GenDecomposedLUTSignedAssign generates a decomposed LUT based on function f over signed messages and writes it to lutOut.
+// Input and output of f is in [-MessageModulus/2, MessageModulus/2), using two's complement.
+// Output of f is cut by MessageModulus.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTSignedAssign(f func(int) int, lutOut *DecomposedLookUpTable[T]) {
+	messageModulus := e.Parameters.messageModulus
+	fFull := func(x int) T {
+		return e.EncodeLWESignedCustom(f(toSignedMessage(x, int(messageModulus))), messageModulus, e.Parameters.scale).Value
+	}
+	e.lutEvaluator.GenLookUpTableNegDecomposedFullAssign(fFull, messageModulus, lutOut)
+}
+
+// GenDecomposedLUTMultiValue generates a decomposed LUT packing functions fs.
+// Input and output of each function is cut by MessageModulus.
+// See [*Evaluator.GenLookUpTableNegDecomposedMultiValueFullAssign] for details.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTMultiValue(fs []func(int) int) DecomposedLookUpTable[T] {
+	lutOut := e.NewDecomposedLUT()
+	e.GenDecomposedLUTMultiValueAssign(fs, &lutOut)
+	return lutOut
+}
+
+// GenDecomposedLUTMultiValueAssign generates a decomposed LUT packing functions fs and writes it to lutOut.
+// Input and output of each function is cut by MessageModulus.
+// See [*Evaluator.GenLookUpTableNegDecomposedMultiValueFullAssign] for details.
+func (e *FullDomainEvaluator[T]) GenDecomposedLUTMultiValueAssign(fs []func(int) int, lutOut *DecomposedLookUpTable[T]) {
+	e.lutEvaluator.GenLookUpTableNegDecomposedMultiValueAssign(fs, e.Parameters.messageModulus, e.Parameters.scale, lutOut)
+}
+
+// BootstrapFunc returns a bootstrapped LWE ciphertext with respect to given function.
+func (e *FullDomainEvaluator[T]) BootstrapFunc(ct LWECiphertext[T], f func(int) int) LWECiphertext[T] {
+	e.GenDecomposedLUTAssign(f, &e.buffer.lut)
+	return e.BootstrapLUT(ct, e.buffer.lut)
+}
+
+// BootstrapFuncAssign bootstraps LWE ciphertext with respect to given function and writes it to ctOut.
+func (e *FullDomainEvaluator[T]) BootstrapFuncAssign(ct LWECiphertext[T], f func(int) int, ctOut LWECiphertext[T]) {
+	e.GenDecomposedLUTAssign(f, &e.buffer.lut)
+	e.BootstrapLUTAssign(ct, e.buffer.lut, ctOut)
+}
+
+// BootstrapFuncSigned returns a bootstrapped LWE ciphertext with respect to given function over signed messages.
+// See [*FullDomainEvaluator.GenDecomposedLUTSigned] for details.
+func (e *FullDomainEvaluator[T]) BootstrapFuncSigned(ct LWECiphertext[T], f func(int) int) LWECiphertext[T] {
+	e.GenDecomposedLUTSignedAssign(f, &e.buffer.lut)
+	return e.BootstrapLUT(ct, e.buffer.lut)
+}
+
+// BootstrapFuncSignedAssign bootstraps LWE ciphertext with respect to given function over signed messages and writes it to ctOut.
+// See [*FullDomainEvaluator.GenDecomposedLUTSigned] for details.
+func (e *FullDomainEvaluator[T]) BootstrapFuncSignedAssign(ct LWECiphertext[T], f func(int) int, ctOut LWECiphertext[T]) {
+	e.GenDecomposedLUTSignedAssign(f, &e.buffer.lut)
+	e.BootstrapLUTAssign(ct, e.buffer.lut, ctOut)
+}
+
+// BootstrapLUT returns a bootstrapped LWE ciphertext with respect to given decomposed LUT.
+func (e *FullDomainEvaluator[T]) BootstrapLUT(ct LWECiphertext[T], lut DecomposedLookUpTable[T]) LWECiphertext[T] {
+	ctOut := NewLWECiphertext(e.Parameters)
+	e.BootstrapLUTAssign(ct, lut, ctOut)
+	return ctOut
+}
+
+// BootstrapLUTAssign bootstraps LWE ciphertext with respect to given decomposed LUT and writes it to ctOut.
+//
+// NegLUTs[i] is evaluated by the evaluator of depth i,
+// and BaseLUT is evaluated by the last evaluator after compressing the input to the base ring.
+//
+// Panics when lut was not built for the parameters of this FullDomainEvaluator.
+func (e *FullDomainEvaluator[T]) BootstrapLUTAssign(ct LWECiphertext[T], lut DecomposedLookUpTable[T], ctOut LWECiphertext[T]) {
+	checkDecomposedLUT(lut, e.Parameters, false)
+
+	if e.compressEvaluator != nil {
+		e.bootstrapLUTConcurrentAssign(ct, lut, ctOut)
+		return
+	}
+
+	last := e.Evaluators[len(e.Evaluators)-1]
+
+	e.buffer.ctAcc.Clear()
+	for i, eval := range e.Evaluators {
+		eval.BootstrapLUTWithMSconstAssign(ct, lut.NegLUTs[i], e.modSwitchConstant, e.buffer.ctBootstrap)
+		eval.AddLWEAssign(e.buffer.ctAcc, e.buffer.ctBootstrap, e.buffer.ctAcc)
+	}
+
+	last.BootstrapLUTWithMSconstAssign(ct, e.compressLUT, 2*e.modSwitchConstant, e.buffer.ctCompress)
+	last.BootstrapLUTAssign(e.buffer.ctCompress, lut.BaseLUT, e.buffer.ctBootstrap)
+	last.AddLWEAssign(e.buffer.ctAcc, e.buffer.ctBootstrap, ctOut)
+}
+
+// bootstrapLUTConcurrentAssign is the concurrent version of BootstrapLUTAssign.
+// NegLUTs[i] is evaluated by the evaluator of depth i,
+// and BaseLUT is evaluated by compressEvaluator, each in its own goroutine.
+func (e *FullDomainEvaluator[T]) bootstrapLUTConcurrentAssign(ct LWECiphertext[T], lut DecomposedLookUpTable[T], ctOut LWECiphertext[T]) {
+	depth := len(e.Evaluators)
+
+	var wg sync.WaitGroup
+	wg.Add(depth + 1)
+	for i := 0; i < depth; i++ {
+		go func(i int) {
+			e.Evaluators[i].BootstrapLUTWithMSconstAssign(ct, lut.NegLUTs[i], e.modSwitchConstant, e.buffer.ctBranch[i])
+			wg.Done()
+		}(i)
+	}
+	go func() {
+		e.compressEvaluator.BootstrapLUTWithMSconstAssign(ct, e.compressLUT, 2*e.modSwitchConstant, e.buffer.ctCompress)
+		e.compressEvaluator.BootstrapLUTAssign(e.buffer.ctCompress, lut.BaseLUT, e.buffer.ctBranch[depth])
+		wg.Done()
+	}()
+	wg.Wait()
+
+	ctOut.CopyFrom(e.buffer.ctBranch[0])
+	for i := 1; i < depth+1; i++ {
+		e.Evaluators[0].AddLWEAssign(ctOut, e.buffer.ctBranch[i], ctOut)
+	}
+}
+
+// BootstrapFuncMultiValue returns bootstrapped LWE ciphertexts with respect to given functions,
+// one for each function.
+// The number of blind rotations is the same as [*FullDomainEvaluator.BootstrapFunc],
+// but the error tolerance is reduced, as estimated by [Parameters.EstimateFailureProbabilityNewFDFBMultiValue].
+func (e *FullDomainEvaluator[T]) BootstrapFuncMultiValue(ct LWECiphertext[T], fs []func(int) int) []LWECiphertext[T] {
+	e.GenDecomposedLUTMultiValueAssign(fs, &e.buffer.lut)
+	return e.BootstrapLUTMultiValue(ct, e.buffer.lut)
+}
+
+// BootstrapFuncMultiValueAssign bootstraps LWE ciphertext with respect to given functions and writes it to ctOut.
+// ctOut[j] is the output of fs[j].
+//
+// Panics when len(ctOut) is not equal to len(fs).
+func (e *FullDomainEvaluator[T]) BootstrapFuncMultiValueAssign(ct LWECiphertext[T], fs []func(int) int, ctOut []LWECiphertext[T]) {
+	e.GenDecomposedLUTMultiValueAssign(fs, &e.buffer.lut)
+	e.BootstrapLUTMultiValueAssign(ct, e.buffer.lut, ctOut)
+}
+
+// BootstrapLUTMultiValue returns bootstrapped LWE ciphertexts with respect to given decomposed LUT,
+// one for each function packed in lut.
+func (e *FullDomainEvaluator[T]) BootstrapLUTMultiValue(ct LWECiphertext[T], lut DecomposedLookUpTable[T]) []LWECiphertext[T] {
+	ctOut := make([]LWECiphertext[T], lut.ValueCount)
+	for i := range ctOut {
+		ctOut[i] = NewLWECiphertext(e.Parameters)
+	}
+	e.BootstrapLUTMultiValueAssign(ct, lut, ctOut)
+	return ctOut
+}
+
+// BootstrapLUTMultiValueAssign bootstraps LWE ciphertext with respect to given decomposed LUT and writes it to ctOut.
+// ctOut[j] is the output of the j-th function packed in lut.
+//
+// Each NegLUT, the compress LUT and the BaseLUT is blind rotated only once,
+// and every output is extracted from the same blind rotation result.
+// ct and ctOut should not overlap.
+//
+// Panics when lut was not built for the parameters of this FullDomainEvaluator,
+// or when len(ctOut) is not equal to lut.ValueCount.
+func (e *FullDomainEvaluator[T]) BootstrapLUTMultiValueAssign(ct LWECiphertext[T], lut DecomposedLookUpTable[T], ctOut []LWECiphertext[T]) {
+	checkDecomposedLUT(lut, e.Parameters, false)
+	if len(ctOut) != lut.ValueCount {
+		panic("ctOut length not equal to ValueCount")
+	}
+
+	for j := range ctOut {
+		ctOut[j].Clear()
+	}
+
+	for i, eval := range e.Evaluators {
+		eval.blindRotateWithMSconstAssign(ct, lut.NegLUTs[i], e.modSwitchConstant, eval.buffer.ctRotate)
+		e.extractMultiValueAddAssign(eval, lut, ctOut)
+	}
+
+	last := e.Evaluators[len(e.Evaluators)-1]
+	last.BootstrapLUTWithMSconstAssign(ct, e.compressLUT, 2*e.modSwitchConstant, e.buffer.ctCompress)
+	last.BlindRotateAssign(e.buffer.ctCompress, lut.BaseLUT, last.buffer.ctRotate)
+	e.extractMultiValueAddAssign(last, lut, ctOut)
+}
+
+// extractMultiValueAddAssign extracts every function packed in lut
+// from the blind rotation result of eval, key switches it, and adds it to ctOut.
+func (e *FullDomainEvaluator[T]) extractMultiValueAddAssign(eval *Evaluator[T], lut DecomposedLookUpTable[T], ctOut []LWECiphertext[T]) {
+	for j := range ctOut {
+		eval.buffer.ctRotate.ToLWECiphertextAssign(lut.valueIndex(j), eval.buffer.ctExtract)
+		eval.KeySwitchForBootstrapAssign(eval.buffer.ctExtract, e.buffer.ctBootstrap)
+		eval.AddLWEAssign(ctOut[j], e.buffer.ctBootstrap, ctOut[j])
+	}
+}
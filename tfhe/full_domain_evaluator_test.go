@@ -0,0 +1,489 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+// testMessages returns every message in [0, messageModulus).
+// In short mode, only the messages around zero and messageModulus / 2 are returned,
+// since bootstrapping every message takes minutes for large precisions.
+func testMessages(messageModulus int) []int {
+	if testing.Short() {
+		return []int{0, 1, messageModulus/2 - 1, messageModulus / 2, messageModulus - 1}
+	}
+
+	xs := make([]int, messageModulus)
+	for x := range xs {
+		xs[x] = x
+	}
+	return xs
+}
+
+func TestFullDomainEvaluator(t *testing.T) {
+	for _, params := range paramsListNew {
+		params := params.Compile()
+		messageModulus := int(params.MessageModulus())
+
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+
+		f := func(x int) int { return 3*x + 1 }
+
+		t.Run(fmt.Sprintf("BootstrapFunc/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			for _, x := range []int{0, messageModulus/2 - 1, messageModulus - 1} {
+				ct := eval.BootstrapFunc(enc[0].EncryptLWE(x), f)
+				assert.Equal(t, f(x)%messageModulus, enc[0].DecryptLWE(ct))
+			}
+		})
+
+		t.Run(fmt.Sprintf("BootstrapLUTAssign/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			lut := eval.GenDecomposedLUT(f)
+			evalCopy := eval.ShallowCopy()
+
+			for _, x := range testMessages(messageModulus) {
+				ct := enc[0].EncryptLWE(x)
+				evalCopy.BootstrapLUTAssign(ct, lut, ct)
+				assert.Equal(t, f(x)%messageModulus, enc[0].DecryptLWE(ct), "f(%v)", x)
+			}
+		})
+
+		testFullDomainEvaluatorSigned(t, params, enc, eval)
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		params := tfhe.Params6.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		evalConcurrent := eval.ShallowCopy()
+		evalConcurrent.SetConcurrent(true)
+
+		f := func(x int) int { return 5*x + 3 }
+		for _, x := range []int{0, 17, 63} {
+			ct := enc[0].EncryptLWE(x)
+			ctOut := evalConcurrent.BootstrapFunc(ct, f)
+			assert.Equal(t, eval.BootstrapFunc(ct, f), ctOut)
+			assert.Equal(t, f(x)%64, enc[0].DecryptLWE(ctOut))
+
+			evalConcurrent.ShallowCopy().BootstrapFuncAssign(ct, f, ct)
+			assert.Equal(t, ctOut, ct)
+		}
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		paramsEBS := tfhe.ParamsEBS5.Compile()
+		assert.Panics(t, func() {
+			tfhe.NewFullDomainEvaluator(params, tfhe.HierarchicalEvaluationKey[uint64]{Parameters: params})
+		})
+		assert.Panics(t, func() {
+			tfhe.NewFullDomainEvaluator(params, tfhe.HierarchicalEvaluationKey[uint64]{Parameters: paramsEBS})
+		})
+		assert.Panics(t, func() {
+			tfhe.NewFullDomainEvaluator(paramsEBS, tfhe.HierarchicalEvaluationKey[uint64]{Parameters: paramsEBS})
+		})
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		evk := tfhe.GenHierarchicalEvaluationKeyParallel(params, enc)
+
+		data, err := evk.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, evk.ByteSize(), len(data))
+
+		var evkOut tfhe.HierarchicalEvaluationKey[uint64]
+		assert.NoError(t, evkOut.UnmarshalBinary(data))
+		assert.Equal(t, params.HierarchyDepth(), evkOut.Depth())
+		assert.Equal(t, evk.Parameters, evkOut.Parameters)
+		assert.Equal(t, evk.Value, evkOut.Value)
+
+		eval := tfhe.NewFullDomainEvaluator(evkOut.Parameters, evkOut)
+		ct := eval.BootstrapFunc(enc[0].EncryptLWE(7), func(x int) int { return 2 * x })
+		assert.Equal(t, 14, enc[0].DecryptLWE(ct))
+
+		// Keys of each depth should have the shape of Parameters.AtDepth(depth+1).
+		paramsDepth := params.AtDepth(1)
+		evkKeySwitch := tfhe.NewEvaluationKey(paramsDepth)
+		evkKeySwitch.KeySwitchKey = tfhe.NewKeySwitchKeyForBootstrapCustom(paramsDepth.LWEDimension(), paramsDepth.GLWERank(), paramsDepth.PolyDegree(), paramsDepth.BlindRotateParameters())
+		for _, evkInvalid := range []tfhe.EvaluationKey[uint64]{
+			tfhe.NewEvaluationKey(params),
+			tfhe.NewEvaluationKey(paramsDepth.Literal().WithKeySwitchMethod(tfhe.KeySwitchMethodGLWE).Compile()),
+			evkKeySwitch,
+		} {
+			data, err := tfhe.HierarchicalEvaluationKey[uint64]{Parameters: params, Value: []tfhe.EvaluationKey[uint64]{evkInvalid}}.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Error(t, evkOut.UnmarshalBinary(data))
+		}
+
+		paramsGLWE := params.Literal().WithKeySwitchMethod(tfhe.KeySwitchMethodGLWE).Compile()
+		evkGLWE := tfhe.GenHierarchicalEvaluationKeyParallel(paramsGLWE, tfhe.NewEncryptorHierarchyWithSharedLWEKey(paramsGLWE))
+		data, err = evkGLWE.MarshalBinary()
+		assert.NoError(t, err)
+		assert.NoError(t, evkOut.UnmarshalBinary(data))
+		assert.Equal(t, evkGLWE, evkOut)
+	})
+}
+
+func TestFullDomainEvaluatorMultiValue(t *testing.T) {
+	for _, tc := range []struct {
+		params tfhe.ParametersLiteral[uint32]
+		fs     []func(int) int
+	}{
+		{tfhe.Params2Uint32, []func(int) int{func(x int) int { return x / 2 }, func(x int) int { return x % 2 }, func(x int) int { return 3*x + 1 }}},
+		{tfhe.Params3Uint32, []func(int) int{func(x int) int { return x / 3 }, func(x int) int { return x % 3 }}},
+	} {
+		params := tc.params.Compile()
+		messageModulus := int(params.MessageModulus())
+
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+
+		t.Run(fmt.Sprintf("FailureProbability/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			assert.Equal(t, params.EstimateFailureProbabilityNewFDFB(), params.EstimateFailureProbabilityNewFDFBMultiValue(1))
+			assert.LessOrEqual(t, math.Log2(params.EstimateFailureProbabilityNewFDFBMultiValue(len(tc.fs))), -60.0)
+		})
+
+		t.Run(fmt.Sprintf("BootstrapFuncMultiValue/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			for x := 0; x < messageModulus; x++ {
+				ctOut := eval.BootstrapFuncMultiValue(enc[0].EncryptLWE(x), tc.fs)
+				assert.Equal(t, len(tc.fs), len(ctOut))
+				for j, f := range tc.fs {
+					assert.Equal(t, f(x)%messageModulus, enc[0].DecryptLWE(ctOut[j]))
+				}
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		params := tfhe.Params2Uint32.Compile()
+		eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint32]{})
+		lut := eval.NewDecomposedLut()
+		assert.Panics(t, func() {
+			eval.GenLookUpTableNegDecomposedMultiValueAssign(nil, params.MessageModulus(), params.Scale(), &lut)
+		})
+
+		fs := make([]func(int) int, params.LookUpTableSize()/int(params.MessageModulus())+1)
+		for i := range fs {
+			fs[i] = func(x int) int { return x }
+		}
+		assert.Panics(t, func() {
+			eval.GenLookUpTableNegDecomposedMultiValueAssign(fs, params.MessageModulus(), params.Scale(), &lut)
+		})
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		params := tfhe.Params2Uint32.Compile()
+		eval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint32]{})
+		lut := eval.NewDecomposedLut()
+		eval.GenLookUpTableNegDecomposedMultiValueAssign([]func(int) int{func(x int) int { return x }, func(x int) int { return 1 }}, params.MessageModulus(), params.Scale(), &lut)
+
+		data, err := lut.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, lut.ByteSize(), len(data))
+
+		lutOut := tfhe.NewDecomposedLookUpTable(params)
+		assert.NoError(t, lutOut.UnmarshalBinary(data))
+		assert.Equal(t, 2, lutOut.ValueCount)
+		assert.Equal(t, lut, lutOut)
+	})
+}
+
+func TestFullDomainEvaluatorBivariate(t *testing.T) {
+	t.Run("FailureProbability", func(t *testing.T) {
+		for _, tc := range []struct {
+			params                  tfhe.ParametersLiteral[uint64]
+			bivariateMessageModulus uint64
+		}{
+			{tfhe.Params5, 4},
+			{tfhe.Params6, 8},
+			{tfhe.Params7, 4},
+			{tfhe.Params8, 0},
+		} {
+			params := tc.params.Compile()
+			messageModulus1 := params.BivariateMessageModulus()
+			assert.Equal(t, tc.bivariateMessageModulus, messageModulus1, "Params%v", num.Log2(params.MessageModulus()))
+			if messageModulus1 == 0 {
+				assert.Greater(t, math.Log2(params.EstimateFailureProbabilityNewFDFBBivariate(1)), -60.0)
+				continue
+			}
+			assert.LessOrEqual(t, math.Log2(params.EstimateFailureProbabilityNewFDFBBivariate(int(messageModulus1))), -60.0)
+			if messageModulus1 < 1<<(num.Log2(params.MessageModulus())/2) {
+				assert.Greater(t, math.Log2(params.EstimateFailureProbabilityNewFDFBBivariate(int(2*messageModulus1))), -60.0)
+			}
+		}
+	})
+
+	t.Run("Params3Uint32", func(t *testing.T) {
+		params := tfhe.Params3Uint32.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		testFullDomainEvaluatorBivariate(t, enc, eval, int(params.BivariateMessageModulus()))
+	})
+
+	t.Run("Params5", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		testFullDomainEvaluatorBivariate(t, enc, eval, int(params.BivariateMessageModulus()))
+		testFullDomainEvaluatorBivariate(t, enc, eval, 2)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		params := tfhe.Params2Uint32.Compile()
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKey(params, tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)))
+		assert.Panics(t, func() { eval.GenDecomposedLUTBivariate(func(x, y int) int { return x }, 0) })
+		assert.Panics(t, func() { eval.GenDecomposedLUTBivariate(func(x, y int) int { return x }, 5) })
+
+		params = tfhe.Params4Uint32.Compile()
+		assert.Zero(t, params.BivariateMessageModulus())
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval = tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKey(params, enc))
+		ct := enc[0].EncryptLWE(0)
+		assert.Panics(t, func() { eval.BootstrapBivariate(ct, ct, func(x, y int) int { return x }) })
+	})
+}
+
+func TestFullDomainEvaluatorMul(t *testing.T) {
+	// referenceMul mirrors MulLWE over plaintexts.
+	referenceMul := func(x, y, messageModulus int) int {
+		half := messageModulus / 2
+		quarterSquare := func(z int) int { return (z * z) / 4 }
+		top := func(z int) int { return half * (z / half) }
+		cross := func(z int) int { return top(z) * (z % 2) }
+
+		xt, yt := top(x), top(y)
+		xl, yl := x-xt, y-yt
+		out := quarterSquare(xl+yl) - quarterSquare(xl-yl) + cross(xt+yl) + cross(yt+xl)
+		return out % messageModulus
+	}
+
+	t.Run("Semantics", func(t *testing.T) {
+		for logM := 2; logM <= 8; logM++ {
+			messageModulus := 1 << logM
+			for x := 0; x < messageModulus; x++ {
+				for y := 0; y < messageModulus; y++ {
+					assert.Equal(t, (x*y)%messageModulus, referenceMul(x, y, messageModulus), "Mul(%v, %v) mod %v", x, y, messageModulus)
+				}
+			}
+		}
+	})
+
+	t.Run("FailureProbability", func(t *testing.T) {
+		for _, params := range []tfhe.ParametersLiteral[uint64]{tfhe.Params5, tfhe.Params6, tfhe.Params7} {
+			params := params.Compile()
+			assert.LessOrEqual(t, math.Log2(params.EstimateFailureProbabilityNewFDFBMul()), -60.0)
+			assert.Greater(t, params.EstimateFailureProbabilityNewFDFBMul(), params.EstimateFailureProbabilityNewFDFB())
+		}
+		params := tfhe.Params3Uint32.Compile()
+		assert.LessOrEqual(t, math.Log2(params.EstimateFailureProbabilityNewFDFBMul()), -60.0)
+
+		// Params8 is not suitable for MulLWE.
+		params8 := tfhe.Params8.Compile()
+		assert.Greater(t, math.Log2(params8.EstimateFailureProbabilityNewFDFBMul()), -60.0)
+	})
+
+	t.Run("MulLWE/ParamsUint3", func(t *testing.T) {
+		params := tfhe.Params3Uint32.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		testFullDomainEvaluatorMul(t, enc, eval, false)
+	})
+
+	for _, params := range []tfhe.ParametersLiteral[uint64]{tfhe.Params5, tfhe.Params6} {
+		params := params.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+
+		t.Run(fmt.Sprintf("MulLWE/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			testFullDomainEvaluatorMul(t, enc, eval, testing.Short())
+		})
+	}
+}
+
+// testFullDomainEvaluatorMul checks MulLWE over all input pairs,
+// or over pairs of [testMessages] if short is true.
+func testFullDomainEvaluatorMul[T tfhe.TorusInt](t *testing.T, enc []*tfhe.Encryptor[T], eval *tfhe.FullDomainEvaluator[T], short bool) {
+	messageModulus := int(eval.Parameters.MessageModulus())
+	xs := make([]int, messageModulus)
+	for x := range xs {
+		xs[x] = x
+	}
+	if short {
+		xs = testMessages(messageModulus)
+	}
+
+	for _, x := range xs {
+		for _, y := range xs {
+			ct0 := enc[0].EncryptLWE(x)
+			eval.MulLWEAssign(ct0, enc[0].EncryptLWE(y), ct0)
+			assert.Equal(t, (x*y)%messageModulus, enc[0].DecryptLWE(ct0), "Mul(%v, %v)", x, y)
+		}
+	}
+}
+
+// testFullDomainEvaluatorBivariate checks bivariate bootstrapping over all input pairs,
+// with the second input in [0, messageModulus1).
+func testFullDomainEvaluatorBivariate[T tfhe.TorusInt](t *testing.T, enc []*tfhe.Encryptor[T], eval *tfhe.FullDomainEvaluator[T], messageModulus1 int) {
+	messageModulus := int(eval.Parameters.MessageModulus())
+	messageModulus0 := messageModulus / messageModulus1
+	f := func(x, y int) int { return x*y + 3*y + x }
+
+	lut := eval.GenDecomposedLUTBivariate(f, messageModulus1)
+	for x := 0; x < messageModulus0; x++ {
+		ct0 := enc[0].EncryptLWE(x)
+		for y := 0; y < messageModulus1; y++ {
+			ct1 := enc[0].EncryptLWE(y)
+			if messageModulus1 == int(eval.Parameters.BivariateMessageModulus()) && y%2 == 0 {
+				assert.Equal(t, f(x, y)%messageModulus, enc[0].DecryptLWE(eval.BootstrapBivariate(ct0, ct1, f)), "f(%v, %v)", x, y)
+			} else {
+				assert.Equal(t, f(x, y)%messageModulus, enc[0].DecryptLWE(eval.BootstrapBivariateLUT(ct0, ct1, lut, messageModulus1)), "f(%v, %v)", x, y)
+			}
+		}
+	}
+}
+
+func testFullDomainEvaluatorSigned[T tfhe.TorusInt](t *testing.T, params tfhe.Parameters[T], enc []*tfhe.Encryptor[T], eval *tfhe.FullDomainEvaluator[T]) {
+	messageModulus := int(params.MessageModulus())
+	signed := func(x int) int {
+		x = ((x % messageModulus) + messageModulus) % messageModulus
+		if x >= messageModulus/2 {
+			return x - messageModulus
+		}
+		return x
+	}
+
+	fs := []func(int) int{
+		func(x int) int { return num.Max(x, 0) },
+		func(x int) int {
+			switch {
+			case x > 0:
+				return 1
+			case x < 0:
+				return -1
+			}
+			return 0
+		},
+		func(x int) int { return num.Abs(x) },
+	}
+
+	t.Run(fmt.Sprintf("EncodeLWESigned/Prec=%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+		for x := -messageModulus / 2; x < messageModulus/2; x++ {
+			assert.Equal(t, eval.EncodeLWE(x+messageModulus), eval.EncodeLWESigned(x))
+			assert.Equal(t, x, eval.DecodeLWESigned(eval.EncodeLWESigned(x)))
+		}
+		assert.Equal(t, -1, eval.DecodeLWESigned(eval.EncodeLWESigned(messageModulus-1)))
+	})
+
+	t.Run(fmt.Sprintf("GenDecomposedLUTSigned/Prec=%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+		for _, f := range fs {
+			assert.Equal(t, eval.GenDecomposedLUT(func(x int) int { return f(signed(x)) }), eval.GenDecomposedLUTSigned(f))
+		}
+	})
+
+	t.Run(fmt.Sprintf("BootstrapFuncSigned/Prec=%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+		for _, x := range testMessages(messageModulus) {
+			x = signed(x + messageModulus/2)
+			ct := enc[0].EncryptLWESigned(x)
+			for i, f := range fs {
+				assert.Equal(t, signed(f(x)), enc[0].DecryptLWESigned(eval.BootstrapFuncSigned(ct, f)), "fs[%v](%v)", i, x)
+			}
+		}
+	})
+}
+
+func TestFullDomainEvaluatorSigned(t *testing.T) {
+	for _, params := range paramsListUint32 {
+		params := params.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		testFullDomainEvaluatorSigned(t, params, enc, eval)
+	}
+}
+
+func TestHierarchicalSecretKey(t *testing.T) {
+	params := tfhe.Params5.Compile()
+	sk := tfhe.GenHierarchicalSecretKey(params)
+	enc := tfhe.NewEncryptorHierarchyWithKey(sk)
+
+	t.Run("Prefix", func(t *testing.T) {
+		assert.Equal(t, params.HierarchyDepth(), sk.Depth())
+		for i := 0; i < sk.Depth(); i++ {
+			assert.True(t, sk.IsPrefix(i))
+			assert.Same(t, &sk.Value[0].LWELargeKey.Value[0], &sk.Value[i].LWELargeKey.Value[0])
+		}
+
+		skOther := sk.Copy()
+		skOther.Value[skOther.Depth()-1].LWEKey = skOther.Value[skOther.Depth()-1].LWEKey.Copy()
+		skOther.Value[skOther.Depth()-1].LWEKey.Value[0] ^= 1
+		assert.False(t, skOther.IsPrefix(skOther.Depth()-1))
+		assert.Panics(t, func() { tfhe.NewEncryptorHierarchyWithKey(skOther) })
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := sk.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, sk.ByteSize(), len(data))
+
+		var skOut tfhe.HierarchicalSecretKey[uint64]
+		assert.NoError(t, skOut.UnmarshalBinary(data))
+		assert.Equal(t, sk.Parameters, skOut.Parameters)
+		assert.Equal(t, sk.Value, skOut.Value)
+
+		encOut := tfhe.NewEncryptorHierarchyWithKey(skOut)
+		for i := 0; i < skOut.Depth(); i++ {
+			assert.True(t, skOut.IsPrefix(i))
+			assert.Same(t, &skOut.Value[0].LWELargeKey.Value[0], &skOut.Value[i].LWELargeKey.Value[0])
+
+			assert.Equal(t, 3, encOut[i].DecryptLWE(enc[i].EncryptLWE(3)))
+			assert.Equal(t, []int{1, 2}, encOut[i].DecryptGLWE(enc[i].EncryptGLWE([]int{1, 2}))[:2])
+		}
+	})
+}
+
+func Benchmark_OurFDFBConcurrent(b *testing.B) {
+	for _, params := range paramsListNew {
+		params := params.Compile()
+
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+		evalConcurrent := eval.ShallowCopy()
+		evalConcurrent.SetConcurrent(true)
+
+		lut := eval.GenDecomposedLUT(func(x int) int { return 13 + x })
+		ct := enc[0].EncryptLWE(1)
+		ctOut := ct.Copy()
+
+		b.Run(fmt.Sprintf("prec=%v/sequential", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				eval.BootstrapLUTAssign(ct, lut, ctOut)
+			}
+		})
+
+		b.Run(fmt.Sprintf("prec=%v/concurrent", num.Log2(params.MessageModulus())), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				evalConcurrent.BootstrapLUTAssign(ct, lut, ctOut)
+			}
+		})
+	}
+}
+
+func ExampleFullDomainEvaluator() {
+	params := tfhe.Params5.Compile()
+
+	enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+	evk := tfhe.GenHierarchicalEvaluationKeyParallel(params, enc)
+	eval := tfhe.NewFullDomainEvaluator(params, evk)
+
+	ct := enc[0].EncryptLWE(5)
+	ctOut := eval.BootstrapFunc(ct, func(x int) int { return 18 - 3*x })
+	fmt.Println(enc[0].DecryptLWE(ctOut))
+	// Output:
+	// 3
+}
@@ -0,0 +1,81 @@
+package tfhe
+
+// genMulLUTs generates the LUTs for [*FullDomainEvaluator.MulLWE]. These are
+//
+//  0. (M/2) * (x >= M/2), which splits x into its top bit and the rest,
+//  1. floor(x^2 / 4) over unsigned inputs in [0, M),
+//  2. floor(x^2 / 4) over signed inputs in [-M/2, M/2),
+//  3. (M/2) * (x >= M/2) * (x mod 2), which is the cross term of the top bits,
+//
+// where M is MessageModulus.
+func (e *FullDomainEvaluator[T]) genMulLUTs() []DecomposedLookUpTable[T] {
+	half := int(e.Parameters.messageModulus / 2)
+	quarterSquare := func(x int) int { return (x * x) / 4 }
+	return []DecomposedLookUpTable[T]{
+		e.GenDecomposedLUT(func(x int) int { return half * (x / half) }),
+		e.GenDecomposedLUT(quarterSquare),
+		e.GenDecomposedLUTSigned(quarterSquare),
+		e.GenDecomposedLUT(func(x int) int { return half * (x / half) * (x % 2) }),
+	}
+}
+
+// MulLWE returns an LWE ciphertext of x * y mod MessageModulus,
+// where ct0 and ct1 encrypt x and y.
+func (e *FullDomainEvaluator[T]) MulLWE(ct0, ct1 LWECiphertext[T]) LWECiphertext[T] {
+	ctOut := NewLWECiphertext(e.Parameters)
+	e.MulLWEAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// MulLWEAssign computes ctOut = x * y mod MessageModulus,
+// where ct0 and ct1 encrypt x and y.
+//
+// Let M = MessageModulus, and write x = (M/2) * xt + xl and y = (M/2) * yt + yl
+// with xl, yl in [0, M/2). Then
+//
+//	xy = xl * yl + (M/2) * (xt * yl + yt * xl) mod M,
+//
+// since M^2 / 4 = 0 mod M. As xl + yl is in [0, M) and xl - yl is in [-M/2, M/2),
+// xl * yl is computed exactly by the quarter-square identity
+// xl * yl = floor((xl+yl)^2 / 4) - floor((xl-yl)^2 / 4).
+// The cross terms only depend on the top bit and the parity of
+// (M/2) * xt + yl and (M/2) * yt + xl respectively.
+//
+// This takes six full-domain bootstrappings: two for the top bits,
+// and four for the terms above.
+// Two bootstrappings of (x+y) and (x-y) are not enough, because xy mod M is not
+// a function of x+y mod M and x-y mod M: if x+y is odd, then (x, y) and (x + M/2, y + M/2)
+// have the same sum and difference mod M, but their products differ by M/2.
+// So the top bits are removed first, after which xl+yl and xl-yl do not wrap around.
+// The LUTs are generated on the first call.
+//
+// The output is a sum of four bootstrapped outputs, so its error is larger than a single bootstrapping.
+// Use [Parameters.EstimateFailureProbabilityNewFDFBMul] to check whether the parameters are suitable.
+// Params8 is not suitable: its failure probability is about 2^-54, above 2^-60.
+func (e *FullDomainEvaluator[T]) MulLWEAssign(ct0, ct1, ctOut LWECiphertext[T]) {
+	if e.mulLUTs == nil {
+		e.mulLUTs = e.genMulLUTs()
+	}
+
+	eval := e.Evaluators[0]
+	ctMul := e.buffer.ctMul
+
+	e.BootstrapLUTAssign(ct0, e.mulLUTs[0], ctMul[0])
+	e.BootstrapLUTAssign(ct1, e.mulLUTs[0], ctMul[1])
+	eval.SubLWEAssign(ct0, ctMul[0], ctMul[2])
+	eval.SubLWEAssign(ct1, ctMul[1], ctMul[3])
+
+	eval.AddLWEAssign(ctMul[0], ctMul[3], ctMul[0])
+	eval.AddLWEAssign(ctMul[1], ctMul[2], ctMul[1])
+	eval.SubLWEAssign(ctMul[2], ctMul[3], ctOut)
+	eval.AddLWEAssign(ctMul[2], ctMul[3], ctMul[2])
+
+	e.BootstrapLUTAssign(ctMul[0], e.mulLUTs[3], ctMul[0])
+	e.BootstrapLUTAssign(ctMul[1], e.mulLUTs[3], ctMul[1])
+	e.BootstrapLUTAssign(ctMul[2], e.mulLUTs[1], ctMul[2])
+	e.BootstrapLUTAssign(ctOut, e.mulLUTs[2], ctOut)
+
+	eval.SubLWEAssign(ctMul[2], ctOut, ctOut)
+	eval.AddLWEAssign(ctOut, ctMul[0], ctOut)
+	eval.AddLWEAssign(ctOut, ctMul[1], ctOut)
+}
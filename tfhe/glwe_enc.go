@@ -1,6 +1,7 @@
 package tfhe
 
 import (
+	"github.com/sp301415/tfhe-go/math/csprng"
 	"github.com/sp301415/tfhe-go/math/num"
 	"github.com/sp301415/tfhe-go/math/poly"
 	"github.com/sp301415/tfhe-go/math/vec"
@@ -35,8 +36,13 @@ func (e *Encryptor[T]) EncryptGLWEPlaintextAssign(pt GLWEPlaintext[T], ctOut GLW
 // EncryptGLWEBody encrypts the value in the body of GLWE ciphertext and overrides it.
 // This avoids the need for most buffers.
 func (e *Encryptor[T]) EncryptGLWEBody(ct GLWECiphertext[T]) {
+	e.encryptGLWEBodyWithSampler(e.UniformSampler, ct)
+}
+
+// encryptGLWEBodyWithSampler is [*Encryptor.EncryptGLWEBody] where the mask is sampled from s.
+func (e *Encryptor[T]) encryptGLWEBodyWithSampler(s *csprng.UniformSampler[T], ct GLWECiphertext[T]) {
 	for i := 0; i < e.Parameters.glweRank; i++ {
-		e.UniformSampler.SamplePolyAssign(ct.Value[i+1])
+		s.SamplePolyAssign(ct.Value[i+1])
 		e.PolyEvaluator.ShortFourierPolyMulSubPolyAssign(ct.Value[i+1], e.SecretKey.FourierGLWEKey.Value[i], ct.Value[0])
 	}
 
@@ -0,0 +1,230 @@
+package integer
+
+import (
+	"math/bits"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// CRTParameters is the parameters for CRT integers.
+//
+// A CRT integer modulo Modulus is represented by its residues modulo each of Moduli,
+// which are pairwise coprime and not necessarily powers of two.
+// Each residue is encrypted as an LWE ciphertext under BaseParameters,
+// using a custom message modulus of 2 * Moduli[i] and a padding bit,
+// so that the sum of two residues does not wrap around.
+// Residues are reduced by programmable bootstrapping with custom lookup tables.
+type CRTParameters[T tfhe.TorusInt] struct {
+	// baseParameters is the parameters for each residue.
+	baseParameters tfhe.Parameters[T]
+	// moduli are the moduli of each residue.
+	moduli []int
+	// modulus is the product of moduli.
+	modulus uint64
+}
+
+// NewCRTParameters returns the parameters for CRT integers with given moduli.
+// The moduli are copied.
+//
+// Panics when moduli is empty, when some modulus is smaller than two,
+// when some modulus is larger than MessageModulus / 4,
+// when moduli are not pairwise coprime,
+// or when the product of moduli overflows uint64.
+func NewCRTParameters[T tfhe.TorusInt](baseParams tfhe.Parameters[T], moduli []int) CRTParameters[T] {
+	if len(moduli) < 1 {
+		panic("Moduli empty")
+	}
+
+	modulus := uint64(1)
+	for i, m := range moduli {
+		switch {
+		case m < 2:
+			panic("Modulus smaller than two")
+		case m > int(baseParams.MessageModulus()/4):
+			panic("Modulus larger than MessageModulus / 4")
+		case modulus > (1<<64-1)/uint64(m):
+			panic("Product of moduli overflows uint64")
+		}
+
+		for _, mm := range moduli[:i] {
+			if gcd(m, mm) != 1 {
+				panic("Moduli not pairwise coprime")
+			}
+		}
+		modulus *= uint64(m)
+	}
+
+	return CRTParameters[T]{
+		baseParameters: baseParams,
+		moduli:         append([]int(nil), moduli...),
+		modulus:        modulus,
+	}
+}
+
+// gcd returns the greatest common divisor of x and y.
+func gcd(x, y int) int {
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return x
+}
+
+// BaseParameters returns the parameters for each residue.
+func (p CRTParameters[T]) BaseParameters() tfhe.Parameters[T] {
+	return p.baseParameters
+}
+
+// Moduli returns a copy of the moduli of each residue.
+func (p CRTParameters[T]) Moduli() []int {
+	return append([]int(nil), p.moduli...)
+}
+
+// ResidueCount returns the number of residues.
+func (p CRTParameters[T]) ResidueCount() int {
+	return len(p.moduli)
+}
+
+// Modulus returns the modulus of CRT integers.
+// This is equal to the product of Moduli.
+func (p CRTParameters[T]) Modulus() uint64 {
+	return p.modulus
+}
+
+// ResidueMessageModulus returns the custom message modulus of the i-th residue.
+// This is equal to 2 * Moduli[i].
+func (p CRTParameters[T]) ResidueMessageModulus(i int) T {
+	return T(2 * p.moduli[i])
+}
+
+// ResidueScale returns the custom scale of the i-th residue.
+// This is equal to Q / (4 * Moduli[i]), leaving a padding bit.
+func (p CRTParameters[T]) ResidueScale(i int) T {
+	return num.DivRound(T(1)<<(num.SizeT[T]()-2), T(p.moduli[i]))
+}
+
+// CRTCiphertext is a CRT integer ciphertext.
+type CRTCiphertext[T tfhe.TorusInt] struct {
+	// Residues are the LWE ciphertexts of each residue.
+	// This has length ResidueCount.
+	Residues []tfhe.LWECiphertext[T]
+}
+
+// NewCRTCiphertext creates a new CRT integer ciphertext.
+func NewCRTCiphertext[T tfhe.TorusInt](params CRTParameters[T]) CRTCiphertext[T] {
+	residues := make([]tfhe.LWECiphertext[T], len(params.moduli))
+	for i := range residues {
+		residues[i] = tfhe.NewLWECiphertext(params.baseParameters)
+	}
+	return CRTCiphertext[T]{Residues: residues}
+}
+
+// Copy returns a copy of the ciphertext.
+func (ct CRTCiphertext[T]) Copy() CRTCiphertext[T] {
+	residues := make([]tfhe.LWECiphertext[T], len(ct.Residues))
+	for i := range residues {
+		residues[i] = ct.Residues[i].Copy()
+	}
+	return CRTCiphertext[T]{Residues: residues}
+}
+
+// CopyFrom copies values from the ciphertext.
+func (ct *CRTCiphertext[T]) CopyFrom(ctIn CRTCiphertext[T]) {
+	for i := range ct.Residues {
+		ct.Residues[i].CopyFrom(ctIn.Residues[i])
+	}
+}
+
+// Clear clears the ciphertext.
+func (ct *CRTCiphertext[T]) Clear() {
+	for i := range ct.Residues {
+		ct.Residues[i].Clear()
+	}
+}
+
+// CRTEncoder encodes CRT integers to residue plaintexts.
+type CRTEncoder[T tfhe.TorusInt] struct {
+	// Parameters is the parameters for this CRTEncoder.
+	Parameters CRTParameters[T]
+	// BaseEncoder is the Encoder for each residue.
+	BaseEncoder *tfhe.Encoder[T]
+}
+
+// NewCRTEncoder returns a initialized CRTEncoder with given parameters.
+func NewCRTEncoder[T tfhe.TorusInt](params CRTParameters[T]) *CRTEncoder[T] {
+	return &CRTEncoder[T]{
+		Parameters:  params,
+		BaseEncoder: tfhe.NewEncoder(params.baseParameters),
+	}
+}
+
+// EncodeCRT encodes integer message to residue plaintexts.
+// Message is cut by Modulus.
+func (e *CRTEncoder[T]) EncodeCRT(message uint64) []tfhe.LWEPlaintext[T] {
+	ptOut := make([]tfhe.LWEPlaintext[T], len(e.Parameters.moduli))
+	e.EncodeCRTAssign(message, ptOut)
+	return ptOut
+}
+
+// EncodeCRTAssign encodes integer message to residue plaintexts and writes it to ptOut.
+// Message is cut by Modulus.
+func (e *CRTEncoder[T]) EncodeCRTAssign(message uint64, ptOut []tfhe.LWEPlaintext[T]) {
+	for i, m := range e.Parameters.moduli {
+		ptOut[i] = e.EncodeResidue(i, int(message%uint64(m)))
+	}
+}
+
+// EncodeResidue encodes residue message of the i-th modulus to plaintext.
+// Message is cut by 2 * Moduli[i].
+func (e *CRTEncoder[T]) EncodeResidue(i int, message int) tfhe.LWEPlaintext[T] {
+	return e.BaseEncoder.EncodeLWECustom(message, e.Parameters.ResidueMessageModulus(i), e.Parameters.ResidueScale(i))
+}
+
+// DecodeCRT decodes residue plaintexts to integer message,
+// using the Chinese remainder theorem.
+func (e *CRTEncoder[T]) DecodeCRT(pt []tfhe.LWEPlaintext[T]) uint64 {
+	var message uint64
+	for i, m := range e.Parameters.moduli {
+		residue := uint64(e.DecodeResidue(i, pt[i]))
+
+		// Basis of the i-th residue is (Modulus / m) * ((Modulus / m)^-1 mod m).
+		q := e.Parameters.modulus / uint64(m)
+		basis := mulMod(q, uint64(invMod(int(q%uint64(m)), m)), e.Parameters.modulus)
+		message = addMod(message, mulMod(residue, basis, e.Parameters.modulus), e.Parameters.modulus)
+	}
+	return message
+}
+
+// DecodeResidue decodes plaintext to residue message of the i-th modulus.
+// Output is cut by Moduli[i].
+func (e *CRTEncoder[T]) DecodeResidue(i int, pt tfhe.LWEPlaintext[T]) int {
+	return e.BaseEncoder.DecodeLWECustom(pt, e.Parameters.ResidueMessageModulus(i), e.Parameters.ResidueScale(i)) % e.Parameters.moduli[i]
+}
+
+// invMod returns x^-1 mod m.
+// x and m should be coprime.
+func invMod(x, m int) int {
+	r0, r1 := m, x%m
+	t0, t1 := 0, 1
+	for r1 != 0 {
+		q := r0 / r1
+		r0, r1 = r1, r0-q*r1
+		t0, t1 = t1, t0-q*t1
+	}
+	return ((t0 % m) + m) % m
+}
+
+// addMod returns x + y mod m, for x, y < m.
+func addMod(x, y, m uint64) uint64 {
+	if x >= m-y {
+		return x - (m - y)
+	}
+	return x + y
+}
+
+// mulMod returns x * y mod m, for x, y < m.
+func mulMod(x, y, m uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	_, r := bits.Div64(hi, lo, m)
+	return r
+}
@@ -0,0 +1,87 @@
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// CRTEncryptor encrypts and decrypts CRT integers.
+// This is meant to be private, only for clients.
+//
+// CRTEncryptor is not safe for concurrent use.
+// Use [*CRTEncryptor.ShallowCopy] to get a safe copy.
+type CRTEncryptor[T tfhe.TorusInt] struct {
+	// CRTEncoder is an embedded encoder for this CRTEncryptor.
+	*CRTEncoder[T]
+	// Parameters is the parameters for this CRTEncryptor.
+	Parameters CRTParameters[T]
+	// BaseEncryptor is the Encryptor for each residue.
+	BaseEncryptor *tfhe.Encryptor[T]
+}
+
+// NewCRTEncryptor returns a initialized CRTEncryptor with given parameters.
+// It also automatically samples LWE and GLWE key.
+func NewCRTEncryptor[T tfhe.TorusInt](params CRTParameters[T]) *CRTEncryptor[T] {
+	return &CRTEncryptor[T]{
+		CRTEncoder:    NewCRTEncoder(params),
+		Parameters:    params,
+		BaseEncryptor: tfhe.NewEncryptor(params.baseParameters),
+	}
+}
+
+// NewCRTEncryptorWithKey returns a initialized CRTEncryptor with given parameters and key.
+// This does not copy secret keys.
+func NewCRTEncryptorWithKey[T tfhe.TorusInt](params CRTParameters[T], sk tfhe.SecretKey[T]) *CRTEncryptor[T] {
+	return &CRTEncryptor[T]{
+		CRTEncoder:    NewCRTEncoder(params),
+		Parameters:    params,
+		BaseEncryptor: tfhe.NewEncryptorWithKey(params.baseParameters, sk),
+	}
+}
+
+// ShallowCopy returns a shallow copy of this CRTEncryptor.
+// Returned CRTEncryptor is safe for concurrent use.
+func (e *CRTEncryptor[T]) ShallowCopy() *CRTEncryptor[T] {
+	return &CRTEncryptor[T]{
+		CRTEncoder:    e.CRTEncoder,
+		Parameters:    e.Parameters,
+		BaseEncryptor: e.BaseEncryptor.ShallowCopy(),
+	}
+}
+
+// GenEvaluationKey samples a new evaluation key for [CRTEvaluator].
+//
+// This can take a long time.
+// Use [*CRTEncryptor.GenEvaluationKeyParallel] for better key generation performance.
+func (e *CRTEncryptor[T]) GenEvaluationKey() tfhe.EvaluationKey[T] {
+	return e.BaseEncryptor.GenEvaluationKey()
+}
+
+// GenEvaluationKeyParallel samples a new evaluation key for [CRTEvaluator] in parallel.
+func (e *CRTEncryptor[T]) GenEvaluationKeyParallel() tfhe.EvaluationKey[T] {
+	return e.BaseEncryptor.GenEvaluationKeyParallel()
+}
+
+// EncryptCRT encrypts integer message to CRT integer ciphertext.
+// Message is cut by Modulus.
+func (e *CRTEncryptor[T]) EncryptCRT(message uint64) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.EncryptCRTAssign(message, ctOut)
+	return ctOut
+}
+
+// EncryptCRTAssign encrypts integer message to CRT integer ciphertext and writes it to ctOut.
+// Message is cut by Modulus.
+func (e *CRTEncryptor[T]) EncryptCRTAssign(message uint64, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		e.BaseEncryptor.EncryptLWEPlaintextAssign(e.EncodeResidue(i, int(message%uint64(m))), ctOut.Residues[i])
+	}
+}
+
+// DecryptCRT decrypts CRT integer ciphertext to integer message.
+func (e *CRTEncryptor[T]) DecryptCRT(ct CRTCiphertext[T]) uint64 {
+	pt := make([]tfhe.LWEPlaintext[T], len(e.Parameters.moduli))
+	for i := range pt {
+		pt[i] = e.BaseEncryptor.DecryptLWEPhase(ct.Residues[i])
+	}
+	return e.DecodeCRT(pt)
+}
@@ -0,0 +1,178 @@
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// CRTEvaluator evaluates homomorphic operations on CRT integers.
+// All ciphertexts should be encrypted with [CRTEncryptor].
+// This is meant to be public, usually for servers.
+//
+// Each residue of the ciphertexts returned by CRTEvaluator is in [0, Moduli[i]),
+// and is the output of a single programmable bootstrapping.
+//
+// CRTEvaluator is not safe for concurrent use.
+// Use [*CRTEvaluator.ShallowCopy] to get a safe copy.
+type CRTEvaluator[T tfhe.TorusInt] struct {
+	// CRTEncoder is an embedded encoder for this CRTEvaluator.
+	*CRTEncoder[T]
+	// Parameters is the parameters for this CRTEvaluator.
+	Parameters CRTParameters[T]
+	// BaseEvaluator is the Evaluator for each residue.
+	BaseEvaluator *tfhe.Evaluator[T]
+
+	// lutReduce[i] is the LUT for x mod Moduli[i].
+	lutReduce []tfhe.LookUpTable[T]
+
+	buffer crtEvaluationBuffer[T]
+}
+
+// crtEvaluationBuffer is a buffer for CRTEvaluator.
+type crtEvaluationBuffer[T tfhe.TorusInt] struct {
+	// ctResidue is the residue before reduction.
+	ctResidue tfhe.LWECiphertext[T]
+	// lut is an empty LUT.
+	lut tfhe.LookUpTable[T]
+}
+
+// NewCRTEvaluator creates a new CRTEvaluator based on parameters.
+// This does not copy evaluation keys, since they may be large.
+func NewCRTEvaluator[T tfhe.TorusInt](params CRTParameters[T], evk tfhe.EvaluationKey[T]) *CRTEvaluator[T] {
+	baseEvaluator := tfhe.NewEvaluator(params.baseParameters, evk)
+
+	lutReduce := make([]tfhe.LookUpTable[T], len(params.moduli))
+	for i, m := range params.moduli {
+		lutReduce[i] = baseEvaluator.GenLookUpTableCustom(func(x int) int { return x % m }, params.ResidueMessageModulus(i), params.ResidueScale(i))
+	}
+
+	return &CRTEvaluator[T]{
+		CRTEncoder:    NewCRTEncoder(params),
+		Parameters:    params,
+		BaseEvaluator: baseEvaluator,
+
+		lutReduce: lutReduce,
+
+		buffer: newCRTEvaluationBuffer(params),
+	}
+}
+
+// newCRTEvaluationBuffer creates a new crtEvaluationBuffer.
+func newCRTEvaluationBuffer[T tfhe.TorusInt](params CRTParameters[T]) crtEvaluationBuffer[T] {
+	return crtEvaluationBuffer[T]{
+		ctResidue: tfhe.NewLWECiphertext(params.baseParameters),
+		lut:       tfhe.NewLookUpTable(params.baseParameters),
+	}
+}
+
+// ShallowCopy returns a shallow copy of this CRTEvaluator.
+// Returned CRTEvaluator is safe for concurrent use.
+func (e *CRTEvaluator[T]) ShallowCopy() *CRTEvaluator[T] {
+	return &CRTEvaluator[T]{
+		CRTEncoder:    e.CRTEncoder,
+		Parameters:    e.Parameters,
+		BaseEvaluator: e.BaseEvaluator.ShallowCopy(),
+
+		lutReduce: e.lutReduce,
+
+		buffer: newCRTEvaluationBuffer(e.Parameters),
+	}
+}
+
+// Add returns ct0 + ct1 mod Modulus.
+func (e *CRTEvaluator[T]) Add(ct0, ct1 CRTCiphertext[T]) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.AddAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// AddAssign computes ctOut = ct0 + ct1 mod Modulus.
+func (e *CRTEvaluator[T]) AddAssign(ct0, ct1, ctOut CRTCiphertext[T]) {
+	for i := range e.Parameters.moduli {
+		e.BaseEvaluator.AddLWEAssign(ct0.Residues[i], ct1.Residues[i], e.buffer.ctResidue)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctResidue, e.lutReduce[i], ctOut.Residues[i])
+	}
+}
+
+// ScalarAdd returns ct0 + c mod Modulus.
+func (e *CRTEvaluator[T]) ScalarAdd(ct0 CRTCiphertext[T], c uint64) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.ScalarAddAssign(ct0, c, ctOut)
+	return ctOut
+}
+
+// ScalarAddAssign computes ctOut = ct0 + c mod Modulus.
+func (e *CRTEvaluator[T]) ScalarAddAssign(ct0 CRTCiphertext[T], c uint64, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		e.BaseEvaluator.AddPlainLWEAssign(ct0.Residues[i], e.EncodeResidue(i, int(c%uint64(m))), e.buffer.ctResidue)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctResidue, e.lutReduce[i], ctOut.Residues[i])
+	}
+}
+
+// Sub returns ct0 - ct1 mod Modulus.
+func (e *CRTEvaluator[T]) Sub(ct0, ct1 CRTCiphertext[T]) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.SubAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// SubAssign computes ctOut = ct0 - ct1 mod Modulus.
+func (e *CRTEvaluator[T]) SubAssign(ct0, ct1, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		e.BaseEvaluator.SubLWEAssign(ct0.Residues[i], ct1.Residues[i], e.buffer.ctResidue)
+		e.BaseEvaluator.AddPlainLWEAssign(e.buffer.ctResidue, e.EncodeResidue(i, m), e.buffer.ctResidue)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctResidue, e.lutReduce[i], ctOut.Residues[i])
+	}
+}
+
+// Neg returns -ct0 mod Modulus.
+func (e *CRTEvaluator[T]) Neg(ct0 CRTCiphertext[T]) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.NegAssign(ct0, ctOut)
+	return ctOut
+}
+
+// NegAssign computes ctOut = -ct0 mod Modulus.
+func (e *CRTEvaluator[T]) NegAssign(ct0, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		e.BaseEvaluator.NegLWEAssign(ct0.Residues[i], e.buffer.ctResidue)
+		e.BaseEvaluator.AddPlainLWEAssign(e.buffer.ctResidue, e.EncodeResidue(i, m), e.buffer.ctResidue)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctResidue, e.lutReduce[i], ctOut.Residues[i])
+	}
+}
+
+// ScalarMul returns c * ct0 mod Modulus.
+func (e *CRTEvaluator[T]) ScalarMul(ct0 CRTCiphertext[T], c uint64) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.ScalarMulAssign(ct0, c, ctOut)
+	return ctOut
+}
+
+// ScalarMulAssign computes ctOut = c * ct0 mod Modulus.
+// Each residue is multiplied by bootstrapping, so the noise does not grow with c.
+func (e *CRTEvaluator[T]) ScalarMulAssign(ct0 CRTCiphertext[T], c uint64, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		ci := int(c % uint64(m))
+		e.BaseEvaluator.GenLookUpTableCustomAssign(func(x int) int { return (x * ci) % m }, e.Parameters.ResidueMessageModulus(i), e.Parameters.ResidueScale(i), e.buffer.lut)
+		e.BaseEvaluator.BootstrapLUTAssign(ct0.Residues[i], e.buffer.lut, ctOut.Residues[i])
+	}
+}
+
+// BootstrapFunc returns a bootstrapped CRT ciphertext
+// with f applied to each residue.
+func (e *CRTEvaluator[T]) BootstrapFunc(ct0 CRTCiphertext[T], f func(int) int) CRTCiphertext[T] {
+	ctOut := NewCRTCiphertext(e.Parameters)
+	e.BootstrapFuncAssign(ct0, f, ctOut)
+	return ctOut
+}
+
+// BootstrapFuncAssign bootstraps ct0 with f applied to each residue and writes it to ctOut.
+//
+// The i-th residue of ctOut is f(x) mod Moduli[i], where x is the i-th residue of ct0.
+// Therefore, ctOut encrypts f(ct0) mod Modulus only if f commutes with the reduction by each modulus,
+// such as polynomials with integer coefficients.
+func (e *CRTEvaluator[T]) BootstrapFuncAssign(ct0 CRTCiphertext[T], f func(int) int, ctOut CRTCiphertext[T]) {
+	for i, m := range e.Parameters.moduli {
+		e.BaseEvaluator.GenLookUpTableCustomAssign(func(x int) int { return ((f(x) % m) + m) % m }, e.Parameters.ResidueMessageModulus(i), e.Parameters.ResidueScale(i), e.buffer.lut)
+		e.BaseEvaluator.BootstrapLUTAssign(ct0.Residues[i], e.buffer.lut, ctOut.Residues[i])
+	}
+}
@@ -0,0 +1,95 @@
+package integer_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/sp301415/tfhe-go/tfhe/integer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRTParameters(t *testing.T) {
+	baseParams := tfhe.Params5.Compile()
+
+	params := integer.NewCRTParameters(baseParams, []int{3, 5, 7, 8})
+	assert.Equal(t, uint64(840), params.Modulus())
+	assert.Equal(t, 4, params.ResidueCount())
+	assert.Equal(t, uint64(14), params.ResidueMessageModulus(2))
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, nil) })
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, []int{1, 3}) })
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, []int{3, 6}) })
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, []int{4, 6}) })
+
+		// Moduli up to MessageModulus / 4 keep the reduction LUT below the padding bit.
+		assert.NotPanics(t, func() { integer.NewCRTParameters(baseParams, []int{int(baseParams.MessageModulus() / 4)}) })
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, []int{int(baseParams.MessageModulus()/4) + 1}) })
+		assert.Panics(t, func() { integer.NewCRTParameters(baseParams, []int{3, 9}) })
+	})
+}
+
+func TestCRTEncoder(t *testing.T) {
+	params := integer.NewCRTParameters(tfhe.Params5.Compile(), []int{3, 5, 7, 8})
+	enc := integer.NewCRTEncoder(params)
+
+	for m := uint64(0); m < params.Modulus(); m++ {
+		assert.Equal(t, m, enc.DecodeCRT(enc.EncodeCRT(m)))
+	}
+	assert.Equal(t, uint64(1), enc.DecodeCRT(enc.EncodeCRT(params.Modulus()+1)))
+}
+
+func TestCRTEvaluator(t *testing.T) {
+	params := integer.NewCRTParameters(tfhe.Params5.Compile(), []int{3, 5, 7, 8})
+	enc := integer.NewCRTEncryptor(params)
+	eval := integer.NewCRTEvaluator(params, enc.GenEvaluationKeyParallel())
+
+	modulus := params.Modulus()
+	messages := []uint64{0, modulus - 1, rand.Uint64() % modulus}
+	cts := make([]integer.CRTCiphertext[uint64], len(messages))
+	for i, m := range messages {
+		cts[i] = enc.EncryptCRT(m)
+		assert.Equal(t, m, enc.DecryptCRT(cts[i]))
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				assert.Equal(t, (m0+m1)%modulus, enc.DecryptCRT(eval.Add(cts[i], cts[j])), "Add(%v, %v)", m0, m1)
+				assert.Equal(t, (m0+modulus-m1)%modulus, enc.DecryptCRT(eval.Sub(cts[i], cts[j])), "Sub(%v, %v)", m0, m1)
+			}
+			assert.Equal(t, (modulus-m0)%modulus, enc.DecryptCRT(eval.Neg(cts[i])), "Neg(%v)", m0)
+			assert.Equal(t, (m0+100)%modulus, enc.DecryptCRT(eval.ScalarAdd(cts[i], 100)), "ScalarAdd(%v, 100)", m0)
+		}
+	})
+
+	t.Run("ScalarMul", func(t *testing.T) {
+		for i, m := range messages {
+			for _, c := range []uint64{0, 1, 97, modulus - 1} {
+				assert.Equal(t, (m*c)%modulus, enc.DecryptCRT(eval.ScalarMul(cts[i], c)), "ScalarMul(%v, %v)", m, c)
+			}
+		}
+	})
+
+	t.Run("BootstrapFunc", func(t *testing.T) {
+		f := func(x int) int { return x*x*x - 2*x + 1 }
+		for i, m := range messages {
+			x := int64(m)
+			want := ((x*x%int64(modulus)*x-2*x+1)%int64(modulus) + int64(modulus)) % int64(modulus)
+			assert.Equal(t, uint64(want), enc.DecryptCRT(eval.BootstrapFunc(cts[i], f)), "BootstrapFunc(%v)", m)
+		}
+	})
+
+	t.Run("Residues", func(t *testing.T) {
+		// Check every residue of every modulus, which are not powers of two except 8.
+		for i, m := range params.Moduli() {
+			for x := 0; x < m; x++ {
+				ct := enc.BaseEncryptor.EncryptLWEPlaintext(enc.EncodeResidue(i, x))
+				ctAdd := eval.BaseEvaluator.AddLWE(ct, ct)
+				ctOut := eval.BaseEvaluator.BootstrapLUT(ctAdd, eval.BaseEvaluator.GenLookUpTableCustom(func(x int) int { return x % m }, params.ResidueMessageModulus(i), params.ResidueScale(i)))
+				assert.Equal(t, (2*x)%m, enc.DecodeResidue(i, enc.BaseEncryptor.DecryptLWEPhase(ctOut)))
+			}
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// Encryptor encrypts and decrypts radix integers.
+// This is meant to be private, only for clients.
+//
+// Encryptor is not safe for concurrent use.
+// Use [*Encryptor.ShallowCopy] to get a safe copy.
+type Encryptor[T tfhe.TorusInt] struct {
+	// Parameters is the parameters for this Encryptor.
+	Parameters Parameters[T]
+	// BaseEncryptors are the Encryptors for each depth of the hierarchy.
+	// Digits are encrypted with BaseEncryptors[0].
+	BaseEncryptors []*tfhe.Encryptor[T]
+}
+
+// NewEncryptor returns a initialized Encryptor with given parameters.
+// It also automatically samples the keys, using [tfhe.GenHierarchicalSecretKey].
+func NewEncryptor[T tfhe.TorusInt](params Parameters[T]) *Encryptor[T] {
+	return &Encryptor[T]{
+		Parameters:     params,
+		BaseEncryptors: tfhe.NewEncryptorHierarchyWithSharedLWEKey(params.baseParameters),
+	}
+}
+
+// NewEncryptorWithKey returns a initialized Encryptor with given parameters and key.
+// This does not copy secret keys.
+//
+// Panics when the key of some depth is not a prefix of the root key.
+func NewEncryptorWithKey[T tfhe.TorusInt](params Parameters[T], sk tfhe.HierarchicalSecretKey[T]) *Encryptor[T] {
+	return &Encryptor[T]{
+		Parameters:     params,
+		BaseEncryptors: tfhe.NewEncryptorHierarchyWithKey(sk),
+	}
+}
+
+// ShallowCopy returns a shallow copy of this Encryptor.
+// Returned Encryptor is safe for concurrent use.
+func (e *Encryptor[T]) ShallowCopy() *Encryptor[T] {
+	baseEncryptors := make([]*tfhe.Encryptor[T], len(e.BaseEncryptors))
+	for i := range baseEncryptors {
+		baseEncryptors[i] = e.BaseEncryptors[i].ShallowCopy()
+	}
+
+	return &Encryptor[T]{
+		Parameters:     e.Parameters,
+		BaseEncryptors: baseEncryptors,
+	}
+}
+
+// GenEvaluationKey samples a new evaluation key for [Evaluator].
+//
+// This can take a long time.
+// Use [*Encryptor.GenEvaluationKeyParallel] for better key generation performance.
+func (e *Encryptor[T]) GenEvaluationKey() tfhe.HierarchicalEvaluationKey[T] {
+	return tfhe.GenHierarchicalEvaluationKey(e.Parameters.baseParameters, e.BaseEncryptors)
+}
+
+// GenEvaluationKeyParallel samples a new evaluation key for [Evaluator] in parallel.
+func (e *Encryptor[T]) GenEvaluationKeyParallel() tfhe.HierarchicalEvaluationKey[T] {
+	return tfhe.GenHierarchicalEvaluationKeyParallel(e.Parameters.baseParameters, e.BaseEncryptors)
+}
+
+// EncryptInteger encrypts integer message to radix integer ciphertext.
+// Message is cut by 2^Bits.
+func (e *Encryptor[T]) EncryptInteger(message uint64) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.EncryptIntegerAssign(message, ctOut)
+	return ctOut
+}
+
+// EncryptIntegerAssign encrypts integer message to radix integer ciphertext and writes it to ctOut.
+// Message is cut by 2^Bits.
+func (e *Encryptor[T]) EncryptIntegerAssign(message uint64, ctOut Ciphertext[T]) {
+	digitMask := uint64(e.Parameters.DigitModulus() - 1)
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.BaseEncryptors[0].EncryptLWEAssign(int(message&digitMask), ctOut.Digits[i])
+		message >>= e.Parameters.digitBits
+	}
+}
+
+// DecryptInteger decrypts radix integer ciphertext to integer message.
+func (e *Encryptor[T]) DecryptInteger(ct Ciphertext[T]) uint64 {
+	var message uint64
+	for i := e.Parameters.digitCount - 1; i >= 0; i-- {
+		message <<= e.Parameters.digitBits
+		message += uint64(e.BaseEncryptors[0].DecryptLWE(ct.Digits[i]))
+	}
+
+	if e.Parameters.Bits() < 64 {
+		message &= 1<<e.Parameters.Bits() - 1
+	}
+	return message
+}
+
+// DecryptLWEBool decrypts LWE ciphertext from comparisons to boolean value.
+func (e *Encryptor[T]) DecryptLWEBool(ct tfhe.LWECiphertext[T]) bool {
+	return e.BaseEncryptors[0].DecryptLWE(ct) == 1
+}
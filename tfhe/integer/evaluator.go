@@ -0,0 +1,266 @@
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// Evaluator evaluates homomorphic operations on radix integers.
+// All ciphertexts should be encrypted with [Encryptor].
+// This is meant to be public, usually for servers.
+//
+// Each digit of the ciphertexts returned by Evaluator is in [0, DigitModulus),
+// and is the output of a single full-domain bootstrapping or a linear function of it
+// with the same noise variance.
+//
+// Evaluator is not safe for concurrent use.
+// Use [*Evaluator.ShallowCopy] to get a safe copy.
+type Evaluator[T tfhe.TorusInt] struct {
+	// Parameters is the parameters for this Evaluator.
+	Parameters Parameters[T]
+
+	// BaseEvaluator is the FullDomainEvaluator for each digit.
+	BaseEvaluator *tfhe.FullDomainEvaluator[T]
+
+	// lutMod is the LUT for x mod DigitModulus.
+	lutMod tfhe.DecomposedLookUpTable[T]
+	// lutDiv is the LUT for x / DigitModulus.
+	lutDiv tfhe.DecomposedLookUpTable[T]
+	// lutSign is the LUT for the sign of signed input.
+	lutSign tfhe.DecomposedLookUpTable[T]
+	// lutSelectHigh is the LUT for x - DigitModulus if x >= DigitModulus, and 0 otherwise.
+	lutSelectHigh tfhe.DecomposedLookUpTable[T]
+	// lutSelectLow is the LUT for x if x < DigitModulus, and 0 otherwise.
+	lutSelectLow tfhe.DecomposedLookUpTable[T]
+	// lutBits[k] is the LUT for DigitModulus * (k-th bit of x).
+	lutBits []tfhe.DecomposedLookUpTable[T]
+
+	buffer evaluationBuffer[T]
+}
+
+// evaluationBuffer is a buffer for Evaluator.
+type evaluationBuffer[T tfhe.TorusInt] struct {
+	// ctRaw holds the digits before carry propagation.
+	ctRaw []tfhe.LWECiphertext[T]
+	// ctAcc is the accumulator for scalar multiplication.
+	ctAcc Ciphertext[T]
+	// ctCarry is the carry during carry propagation.
+	ctCarry tfhe.LWECiphertext[T]
+	// ctDigit is a temporary digit.
+	ctDigit tfhe.LWECiphertext[T]
+	// ctCmp is the result of the comparison of digits.
+	ctCmp tfhe.LWECiphertext[T]
+	// ctSelect is the selector for Min and Max.
+	ctSelect tfhe.LWECiphertext[T]
+
+	// lut is an empty decomposed LUT.
+	lut tfhe.DecomposedLookUpTable[T]
+	// lutHigh is an empty decomposed LUT,
+	// used with lut for the higher digits of products.
+	lutHigh tfhe.DecomposedLookUpTable[T]
+}
+
+// NewEvaluator creates a new Evaluator based on parameters.
+// evk should be generated by [*Encryptor.GenEvaluationKeyParallel] with params.
+// This does not copy evaluation keys, since they may be large.
+func NewEvaluator[T tfhe.TorusInt](params Parameters[T], evk tfhe.HierarchicalEvaluationKey[T]) *Evaluator[T] {
+	baseEvaluator := tfhe.NewFullDomainEvaluator(params.baseParameters, evk)
+	digitModulus := int(params.DigitModulus())
+
+	lutBits := make([]tfhe.DecomposedLookUpTable[T], params.digitBits)
+	for k := range lutBits {
+		k := k
+		lutBits[k] = baseEvaluator.GenDecomposedLUT(func(x int) int { return ((x >> k) & 1) * digitModulus })
+	}
+
+	return &Evaluator[T]{
+		Parameters: params,
+
+		BaseEvaluator: baseEvaluator,
+
+		lutMod: baseEvaluator.GenDecomposedLUT(func(x int) int { return x % digitModulus }),
+		lutDiv: baseEvaluator.GenDecomposedLUT(func(x int) int { return x / digitModulus }),
+		lutSign: baseEvaluator.GenDecomposedLUTSigned(func(x int) int {
+			switch {
+			case x > 0:
+				return 1
+			case x < 0:
+				return -1
+			}
+			return 0
+		}),
+		lutSelectHigh: baseEvaluator.GenDecomposedLUT(func(x int) int {
+			if x >= digitModulus {
+				return x - digitModulus
+			}
+			return 0
+		}),
+		lutSelectLow: baseEvaluator.GenDecomposedLUT(func(x int) int {
+			if x < digitModulus {
+				return x
+			}
+			return 0
+		}),
+		lutBits: lutBits,
+
+		buffer: newEvaluationBuffer(params),
+	}
+}
+
+// newEvaluationBuffer creates a new evaluationBuffer.
+func newEvaluationBuffer[T tfhe.TorusInt](params Parameters[T]) evaluationBuffer[T] {
+	ctRaw := make([]tfhe.LWECiphertext[T], params.digitCount)
+	for i := range ctRaw {
+		ctRaw[i] = tfhe.NewLWECiphertext(params.baseParameters)
+	}
+
+	return evaluationBuffer[T]{
+		ctRaw:    ctRaw,
+		ctAcc:    NewCiphertext(params),
+		ctCarry:  tfhe.NewLWECiphertext(params.baseParameters),
+		ctDigit:  tfhe.NewLWECiphertext(params.baseParameters),
+		ctCmp:    tfhe.NewLWECiphertext(params.baseParameters),
+		ctSelect: tfhe.NewLWECiphertext(params.baseParameters),
+
+		lut:     tfhe.NewDecomposedLookUpTable(params.baseParameters),
+		lutHigh: tfhe.NewDecomposedLookUpTable(params.baseParameters),
+	}
+}
+
+// ShallowCopy returns a shallow copy of this Evaluator.
+// Returned Evaluator is safe for concurrent use.
+func (e *Evaluator[T]) ShallowCopy() *Evaluator[T] {
+	return &Evaluator[T]{
+		Parameters: e.Parameters,
+
+		BaseEvaluator: e.BaseEvaluator.ShallowCopy(),
+
+		lutMod:        e.lutMod,
+		lutDiv:        e.lutDiv,
+		lutSign:       e.lutSign,
+		lutSelectHigh: e.lutSelectHigh,
+		lutSelectLow:  e.lutSelectLow,
+		lutBits:       e.lutBits,
+
+		buffer: newEvaluationBuffer(e.Parameters),
+	}
+}
+
+// lweEvaluator returns the Evaluator for linear operations on digits.
+func (e *Evaluator[T]) lweEvaluator() *tfhe.Evaluator[T] {
+	return e.BaseEvaluator.Evaluators[0]
+}
+
+// propagateCarryAssign propagates the carries of e.buffer.ctRaw
+// from the start-th digit, and writes the digits to ctOut.
+// The digits of ctOut below start are left untouched.
+//
+// Each raw digit should be smaller than MessageModulus - 2.
+// The carry out of the most significant digit is discarded.
+func (e *Evaluator[T]) propagateCarryAssign(start int, ctOut Ciphertext[T]) {
+	eval := e.lweEvaluator()
+	for i := start; i < e.Parameters.digitCount; i++ {
+		if i > start {
+			eval.AddLWEAssign(e.buffer.ctRaw[i], e.buffer.ctCarry, e.buffer.ctRaw[i])
+		}
+		if i < e.Parameters.digitCount-1 {
+			e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctRaw[i], e.lutDiv, e.buffer.ctCarry)
+		}
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctRaw[i], e.lutMod, ctOut.Digits[i])
+	}
+}
+
+// Add returns ct0 + ct1 mod 2^Bits.
+func (e *Evaluator[T]) Add(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.AddAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// AddAssign computes ctOut = ct0 + ct1 mod 2^Bits.
+func (e *Evaluator[T]) AddAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.lweEvaluator().AddLWEAssign(ct0.Digits[i], ct1.Digits[i], e.buffer.ctRaw[i])
+	}
+	e.propagateCarryAssign(0, ctOut)
+}
+
+// Sub returns ct0 - ct1 mod 2^Bits.
+func (e *Evaluator[T]) Sub(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.SubAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// SubAssign computes ctOut = ct0 - ct1 mod 2^Bits.
+func (e *Evaluator[T]) SubAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	// ct0 - ct1 = ct0 + (2^Bits - 1 - ct1) + 1,
+	// where each digit of 2^Bits - 1 - ct1 is DigitModulus - 1 - ct1[i].
+	digitModulus := int(e.Parameters.DigitModulus())
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.lweEvaluator().SubLWEAssign(ct0.Digits[i], ct1.Digits[i], e.buffer.ctRaw[i])
+		e.lweEvaluator().AddPlainLWEAssign(e.buffer.ctRaw[i], e.BaseEvaluator.EncodeLWE(digitModulus-1), e.buffer.ctRaw[i])
+	}
+	e.lweEvaluator().AddPlainLWEAssign(e.buffer.ctRaw[0], e.BaseEvaluator.EncodeLWE(1), e.buffer.ctRaw[0])
+	e.propagateCarryAssign(0, ctOut)
+}
+
+// Neg returns -ct0 mod 2^Bits.
+func (e *Evaluator[T]) Neg(ct0 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.NegAssign(ct0, ctOut)
+	return ctOut
+}
+
+// NegAssign computes ctOut = -ct0 mod 2^Bits.
+func (e *Evaluator[T]) NegAssign(ct0, ctOut Ciphertext[T]) {
+	digitModulus := int(e.Parameters.DigitModulus())
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.lweEvaluator().NegLWEAssign(ct0.Digits[i], e.buffer.ctRaw[i])
+		e.lweEvaluator().AddPlainLWEAssign(e.buffer.ctRaw[i], e.BaseEvaluator.EncodeLWE(digitModulus-1), e.buffer.ctRaw[i])
+	}
+	e.lweEvaluator().AddPlainLWEAssign(e.buffer.ctRaw[0], e.BaseEvaluator.EncodeLWE(1), e.buffer.ctRaw[0])
+	e.propagateCarryAssign(0, ctOut)
+}
+
+// ScalarMul returns c * ct0 mod 2^Bits.
+func (e *Evaluator[T]) ScalarMul(ct0 Ciphertext[T], c uint64) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.ScalarMulAssign(ct0, c, ctOut)
+	return ctOut
+}
+
+// ScalarMulAssign computes ctOut = c * ct0 mod 2^Bits.
+//
+// For each nonzero digit c[j] of c, the lower and upper digits of ct0[i] * c[j]
+// are computed by bootstrapping, and added to the (i+j)-th and (i+j+1)-th digits of the accumulator.
+func (e *Evaluator[T]) ScalarMulAssign(ct0 Ciphertext[T], c uint64, ctOut Ciphertext[T]) {
+	digitModulus := int(e.Parameters.DigitModulus())
+	digitCount := e.Parameters.digitCount
+
+	e.buffer.ctAcc.Clear()
+	for j := 0; j < digitCount; j++ {
+		cj := int((c >> (j * e.Parameters.digitBits)) & uint64(digitModulus-1))
+		if cj == 0 {
+			continue
+		}
+
+		e.BaseEvaluator.GenDecomposedLUTAssign(func(x int) int { return (x * cj) % digitModulus }, &e.buffer.lut)
+		e.BaseEvaluator.GenDecomposedLUTAssign(func(x int) int { return (x * cj) / digitModulus }, &e.buffer.lutHigh)
+
+		for i := j; i < digitCount; i++ {
+			e.buffer.ctRaw[i].CopyFrom(e.buffer.ctAcc.Digits[i])
+		}
+
+		for i := 0; i+j < digitCount; i++ {
+			e.BaseEvaluator.BootstrapLUTAssign(ct0.Digits[i], e.buffer.lut, e.buffer.ctDigit)
+			e.lweEvaluator().AddLWEAssign(e.buffer.ctRaw[i+j], e.buffer.ctDigit, e.buffer.ctRaw[i+j])
+			if i+j+1 < digitCount {
+				e.BaseEvaluator.BootstrapLUTAssign(ct0.Digits[i], e.buffer.lutHigh, e.buffer.ctDigit)
+				e.lweEvaluator().AddLWEAssign(e.buffer.ctRaw[i+j+1], e.buffer.ctDigit, e.buffer.ctRaw[i+j+1])
+			}
+		}
+
+		e.propagateCarryAssign(j, e.buffer.ctAcc)
+	}
+	ctOut.CopyFrom(e.buffer.ctAcc)
+}
@@ -0,0 +1,88 @@
+package integer
+
+// bitwiseAssign computes ctOut = op(ct0, ct1) bitwise.
+// op receives the bits of ct0 and ct1, and returns the output bit.
+//
+// For each bit position k, the k-th bit of ct0[i] is extracted as DigitModulus * bit,
+// and ct1[i] + DigitModulus * bit is bootstrapped to 2^k * op(bit, k-th bit of ct1[i]).
+// The outputs are summed and cleaned by a final bootstrapping.
+func (e *Evaluator[T]) bitwiseAssign(ct0, ct1 Ciphertext[T], op func(x, y int) int, ctOut Ciphertext[T]) {
+	eval := e.lweEvaluator()
+	digitModulus := int(e.Parameters.DigitModulus())
+
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.buffer.ctRaw[i].Clear()
+	}
+
+	for k := 0; k < e.Parameters.digitBits; k++ {
+		k := k
+		e.BaseEvaluator.GenDecomposedLUTAssign(func(x int) int {
+			return op(x/digitModulus, ((x%digitModulus)>>k)&1) << k
+		}, &e.buffer.lut)
+
+		for i := 0; i < e.Parameters.digitCount; i++ {
+			e.BaseEvaluator.BootstrapLUTAssign(ct0.Digits[i], e.lutBits[k], e.buffer.ctDigit)
+			eval.AddLWEAssign(ct1.Digits[i], e.buffer.ctDigit, e.buffer.ctDigit)
+			e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctDigit, e.buffer.lut, e.buffer.ctDigit)
+			eval.AddLWEAssign(e.buffer.ctRaw[i], e.buffer.ctDigit, e.buffer.ctRaw[i])
+		}
+	}
+
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctRaw[i], e.lutMod, ctOut.Digits[i])
+	}
+}
+
+// And returns ct0 AND ct1.
+func (e *Evaluator[T]) And(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.AndAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// AndAssign computes ctOut = ct0 AND ct1.
+func (e *Evaluator[T]) AndAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	e.bitwiseAssign(ct0, ct1, func(x, y int) int { return x & y }, ctOut)
+}
+
+// Or returns ct0 OR ct1.
+func (e *Evaluator[T]) Or(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.OrAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// OrAssign computes ctOut = ct0 OR ct1.
+func (e *Evaluator[T]) OrAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	e.bitwiseAssign(ct0, ct1, func(x, y int) int { return x | y }, ctOut)
+}
+
+// Xor returns ct0 XOR ct1.
+func (e *Evaluator[T]) Xor(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.XorAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// XorAssign computes ctOut = ct0 XOR ct1.
+func (e *Evaluator[T]) XorAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	e.bitwiseAssign(ct0, ct1, func(x, y int) int { return x ^ y }, ctOut)
+}
+
+// Not returns NOT ct0.
+// This does not use bootstrapping.
+func (e *Evaluator[T]) Not(ct0 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.NotAssign(ct0, ctOut)
+	return ctOut
+}
+
+// NotAssign computes ctOut = NOT ct0.
+// This does not use bootstrapping.
+func (e *Evaluator[T]) NotAssign(ct0, ctOut Ciphertext[T]) {
+	digitModulus := int(e.Parameters.DigitModulus())
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		e.lweEvaluator().NegLWEAssign(ct0.Digits[i], ctOut.Digits[i])
+		e.lweEvaluator().AddPlainLWEAssign(ctOut.Digits[i], e.BaseEvaluator.EncodeLWE(digitModulus-1), ctOut.Digits[i])
+	}
+}
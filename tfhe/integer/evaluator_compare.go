@@ -0,0 +1,152 @@
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// boolToInt returns 1 if b is true, and 0 otherwise.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// compareAssign compares ct0 and ct1, evaluates pred on the result and writes it to ctOut.
+// pred receives -1, 0 or 1 when ct0 is smaller than, equal to or larger than ct1,
+// and its output is encoded as a signed message.
+//
+// The sign of each digit difference is folded from the most significant digit,
+// as r = sign(2r + sign(ct0[i] - ct1[i])).
+func (e *Evaluator[T]) compareAssign(ct0, ct1 Ciphertext[T], pred func(int) int, ctOut tfhe.LWECiphertext[T]) {
+	eval := e.lweEvaluator()
+
+	top := e.Parameters.digitCount - 1
+	eval.SubLWEAssign(ct0.Digits[top], ct1.Digits[top], e.buffer.ctCmp)
+	e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctCmp, e.lutSign, e.buffer.ctCmp)
+	for i := top - 1; i >= 0; i-- {
+		eval.SubLWEAssign(ct0.Digits[i], ct1.Digits[i], e.buffer.ctDigit)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctDigit, e.lutSign, e.buffer.ctDigit)
+		eval.ScalarMulAddLWEAssign(e.buffer.ctCmp, 2, e.buffer.ctDigit)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctDigit, e.lutSign, e.buffer.ctCmp)
+	}
+
+	e.BaseEvaluator.GenDecomposedLUTSignedAssign(pred, &e.buffer.lut)
+	e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctCmp, e.buffer.lut, ctOut)
+}
+
+// Eq returns an LWE encryption of ct0 == ct1.
+func (e *Evaluator[T]) Eq(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.EqAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// EqAssign computes ctOut = ct0 == ct1.
+func (e *Evaluator[T]) EqAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r == 0) }, ctOut)
+}
+
+// Ne returns an LWE encryption of ct0 != ct1.
+func (e *Evaluator[T]) Ne(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.NeAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// NeAssign computes ctOut = ct0 != ct1.
+func (e *Evaluator[T]) NeAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r != 0) }, ctOut)
+}
+
+// Lt returns an LWE encryption of ct0 < ct1.
+func (e *Evaluator[T]) Lt(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.LtAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// LtAssign computes ctOut = ct0 < ct1.
+func (e *Evaluator[T]) LtAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r < 0) }, ctOut)
+}
+
+// Le returns an LWE encryption of ct0 <= ct1.
+func (e *Evaluator[T]) Le(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.LeAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// LeAssign computes ctOut = ct0 <= ct1.
+func (e *Evaluator[T]) LeAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r <= 0) }, ctOut)
+}
+
+// Gt returns an LWE encryption of ct0 > ct1.
+func (e *Evaluator[T]) Gt(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.GtAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// GtAssign computes ctOut = ct0 > ct1.
+func (e *Evaluator[T]) GtAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r > 0) }, ctOut)
+}
+
+// Ge returns an LWE encryption of ct0 >= ct1.
+func (e *Evaluator[T]) Ge(ct0, ct1 Ciphertext[T]) tfhe.LWECiphertext[T] {
+	ctOut := tfhe.NewLWECiphertext(e.Parameters.baseParameters)
+	e.GeAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// GeAssign computes ctOut = ct0 >= ct1.
+func (e *Evaluator[T]) GeAssign(ct0, ct1 Ciphertext[T], ctOut tfhe.LWECiphertext[T]) {
+	e.compareAssign(ct0, ct1, func(r int) int { return boolToInt(r >= 0) }, ctOut)
+}
+
+// selectAssign writes ct0 to ctOut if pred holds on the comparison of ct0 and ct1,
+// and ct1 otherwise.
+//
+// The selector s is encrypted as DigitModulus * s,
+// so that each digit is selected by bootstrapping ct0[i] + DigitModulus * s and ct1[i] + DigitModulus * s.
+func (e *Evaluator[T]) selectAssign(ct0, ct1 Ciphertext[T], pred func(int) int, ctOut Ciphertext[T]) {
+	eval := e.lweEvaluator()
+	digitModulus := int(e.Parameters.DigitModulus())
+
+	e.compareAssign(ct0, ct1, func(r int) int { return pred(r) * digitModulus }, e.buffer.ctSelect)
+	for i := 0; i < e.Parameters.digitCount; i++ {
+		eval.AddLWEAssign(ct0.Digits[i], e.buffer.ctSelect, e.buffer.ctDigit)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctDigit, e.lutSelectHigh, e.buffer.ctRaw[i])
+		eval.AddLWEAssign(ct1.Digits[i], e.buffer.ctSelect, e.buffer.ctDigit)
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctDigit, e.lutSelectLow, e.buffer.ctDigit)
+		eval.AddLWEAssign(e.buffer.ctRaw[i], e.buffer.ctDigit, e.buffer.ctRaw[i])
+		e.BaseEvaluator.BootstrapLUTAssign(e.buffer.ctRaw[i], e.lutMod, ctOut.Digits[i])
+	}
+}
+
+// Min returns min(ct0, ct1).
+func (e *Evaluator[T]) Min(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.MinAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// MinAssign computes ctOut = min(ct0, ct1).
+func (e *Evaluator[T]) MinAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	e.selectAssign(ct0, ct1, func(r int) int { return boolToInt(r < 0) }, ctOut)
+}
+
+// Max returns max(ct0, ct1).
+func (e *Evaluator[T]) Max(ct0, ct1 Ciphertext[T]) Ciphertext[T] {
+	ctOut := NewCiphertext(e.Parameters)
+	e.MaxAssign(ct0, ct1, ctOut)
+	return ctOut
+}
+
+// MaxAssign computes ctOut = max(ct0, ct1).
+func (e *Evaluator[T]) MaxAssign(ct0, ct1, ctOut Ciphertext[T]) {
+	e.selectAssign(ct0, ct1, func(r int) int { return boolToInt(r > 0) }, ctOut)
+}
@@ -0,0 +1,138 @@
+// Package integer implements homomorphic radix integers on top of full-domain bootstrapping.
+package integer
+
+import (
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// Parameters is the parameters for radix integers.
+//
+// A radix integer of Bits bits is represented by DigitCount digits of DigitBits bits,
+// from the least significant digit.
+// Each digit is encrypted as an LWE ciphertext under BaseParameters,
+// and is kept in [0, DigitModulus).
+// The rest of the message space holds the carries and differences of digits,
+// which are cleaned by full-domain bootstrapping without padding bits.
+type Parameters[T tfhe.TorusInt] struct {
+	// baseParameters is the parameters for each digit.
+	baseParameters tfhe.Parameters[T]
+	// digitBits is the number of bits of each digit.
+	digitBits int
+	// digitCount is the number of digits.
+	digitCount int
+}
+
+// NewParameters returns the parameters for radix integers of given bits.
+// DigitBits is chosen as the largest divisor of bits
+// not larger than log(MessageModulus) - 2.
+//
+// Panics when bits is not in [1, 64],
+// or when MessageModulus of baseParams is smaller than 8.
+func NewParameters[T tfhe.TorusInt](baseParams tfhe.Parameters[T], bits int) Parameters[T] {
+	maxDigitBits := num.Log2(baseParams.MessageModulus()) - 2
+	switch {
+	case bits < 1:
+		panic("Bits smaller than one")
+	case maxDigitBits < 1:
+		panic("MessageModulus smaller than 8")
+	}
+
+	digitBits := num.Min(maxDigitBits, bits)
+	for bits%digitBits != 0 {
+		digitBits--
+	}
+	return NewParametersCustom(baseParams, digitBits, bits/digitBits)
+}
+
+// NewParametersCustom returns the parameters for radix integers
+// with given number and size of digits.
+//
+// Panics when digitBits or digitCount is smaller than one,
+// when digitBits * digitCount is larger than 64,
+// or when digitBits is larger than log(MessageModulus) - 2.
+func NewParametersCustom[T tfhe.TorusInt](baseParams tfhe.Parameters[T], digitBits, digitCount int) Parameters[T] {
+	switch {
+	case digitBits < 1:
+		panic("DigitBits smaller than one")
+	case digitCount < 1:
+		panic("DigitCount smaller than one")
+	case digitBits*digitCount > 64:
+		panic("Bits larger than 64")
+	case digitBits > num.Log2(baseParams.MessageModulus())-2:
+		panic("DigitBits larger than log(MessageModulus) - 2")
+	}
+
+	return Parameters[T]{
+		baseParameters: baseParams,
+		digitBits:      digitBits,
+		digitCount:     digitCount,
+	}
+}
+
+// BaseParameters returns the parameters for each digit.
+func (p Parameters[T]) BaseParameters() tfhe.Parameters[T] {
+	return p.baseParameters
+}
+
+// DigitBits returns the number of bits of each digit.
+func (p Parameters[T]) DigitBits() int {
+	return p.digitBits
+}
+
+// DigitModulus returns the modulus of each digit.
+// This is equal to 2^DigitBits.
+func (p Parameters[T]) DigitModulus() T {
+	return 1 << p.digitBits
+}
+
+// DigitCount returns the number of digits.
+func (p Parameters[T]) DigitCount() int {
+	return p.digitCount
+}
+
+// Bits returns the number of bits of radix integers.
+// This is equal to DigitBits * DigitCount.
+func (p Parameters[T]) Bits() int {
+	return p.digitBits * p.digitCount
+}
+
+// Ciphertext is a radix integer ciphertext.
+type Ciphertext[T tfhe.TorusInt] struct {
+	// Digits are the LWE ciphertexts of each digit,
+	// from the least significant digit.
+	// This has length DigitCount.
+	Digits []tfhe.LWECiphertext[T]
+}
+
+// NewCiphertext creates a new radix integer ciphertext.
+func NewCiphertext[T tfhe.TorusInt](params Parameters[T]) Ciphertext[T] {
+	digits := make([]tfhe.LWECiphertext[T], params.digitCount)
+	for i := range digits {
+		digits[i] = tfhe.NewLWECiphertext(params.baseParameters)
+	}
+	return Ciphertext[T]{Digits: digits}
+}
+
+// Copy returns a copy of the ciphertext.
+func (ct Ciphertext[T]) Copy() Ciphertext[T] {
+	digits := make([]tfhe.LWECiphertext[T], len(ct.Digits))
+	for i := range digits {
+		digits[i] = ct.Digits[i].Copy()
+	}
+	return Ciphertext[T]{Digits: digits}
+}
+
+// CopyFrom copies values from the ciphertext.
+func (ct *Ciphertext[T]) CopyFrom(ctIn Ciphertext[T]) {
+	for i := range ct.Digits {
+		ct.Digits[i].CopyFrom(ctIn.Digits[i])
+	}
+}
+
+// Clear clears the ciphertext.
+func (ct *Ciphertext[T]) Clear() {
+	for i := range ct.Digits {
+		ct.Digits[i].Clear()
+	}
+}
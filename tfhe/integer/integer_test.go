@@ -0,0 +1,245 @@
+package integer_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/sp301415/tfhe-go/tfhe/integer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameters(t *testing.T) {
+	baseParams := tfhe.Params5.Compile()
+
+	t.Run("NewParameters", func(t *testing.T) {
+		params := integer.NewParameters(baseParams, 12)
+		assert.Equal(t, 3, params.DigitBits())
+		assert.Equal(t, 4, params.DigitCount())
+
+		params = integer.NewParameters(baseParams, 16)
+		assert.Equal(t, 2, params.DigitBits())
+		assert.Equal(t, 16, params.Bits())
+
+		params = integer.NewParameters(baseParams, 8)
+		assert.Equal(t, 2, params.DigitBits())
+		assert.Equal(t, 4, params.DigitCount())
+		assert.Equal(t, uint64(4), params.DigitModulus())
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { integer.NewParameters(baseParams, 0) })
+		assert.Panics(t, func() { integer.NewParametersCustom(baseParams, 4, 2) })
+		assert.Panics(t, func() { integer.NewParametersCustom(baseParams, 3, 22) })
+		assert.Panics(t, func() { integer.NewParameters(tfhe.Params2Uint32.Compile(), 8) })
+	})
+}
+
+func TestEvaluator(t *testing.T) {
+	t.Run("Uint32", func(t *testing.T) {
+		testEvaluator(t, integer.NewParametersCustom(tfhe.Params4Uint32.Compile(), 1, 2))
+	})
+
+	t.Run("Uint32/DigitBits2", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("exhaustive 4-bit evaluator test skipped in short mode")
+		}
+		testEvaluator(t, integer.NewParametersCustom(tfhe.Params4Uint32.Compile(), 2, 2))
+	})
+
+	t.Run("Uint64", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("exhaustive uint64 evaluator test skipped in short mode")
+		}
+		testEvaluator(t, integer.NewParametersCustom(tfhe.Params5.Compile(), 1, 2))
+	})
+}
+
+// testEvaluator checks every operation of the evaluator
+// on all pairs of messages, so params should be small.
+func testEvaluator[T tfhe.TorusInt](t *testing.T, params integer.Parameters[T]) {
+	enc := integer.NewEncryptor(params)
+	eval := integer.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+	mask := uint64(1<<params.Bits() - 1)
+	messages := make([]uint64, 1<<params.Bits())
+	cts := make([]integer.Ciphertext[T], len(messages))
+	for i := range messages {
+		messages[i] = uint64(i)
+		cts[i] = enc.EncryptInteger(messages[i])
+	}
+
+	t.Run("EncryptInteger", func(t *testing.T) {
+		for i, m := range messages {
+			assert.Equal(t, m, enc.DecryptInteger(cts[i]))
+		}
+		assert.Equal(t, uint64(1), enc.DecryptInteger(enc.EncryptInteger(mask+2)))
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				assert.Equal(t, (m0+m1)&mask, enc.DecryptInteger(eval.Add(cts[i], cts[j])), "Add(%v, %v)", m0, m1)
+				assert.Equal(t, (m0-m1)&mask, enc.DecryptInteger(eval.Sub(cts[i], cts[j])), "Sub(%v, %v)", m0, m1)
+				assert.Equal(t, (m0*m1)&mask, enc.DecryptInteger(eval.ScalarMul(cts[i], m1)), "ScalarMul(%v, %v)", m0, m1)
+			}
+			assert.Equal(t, (-m0)&mask, enc.DecryptInteger(eval.Neg(cts[i])), "Neg(%v)", m0)
+		}
+	})
+
+	t.Run("Lt", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				assert.Equal(t, m0 < m1, enc.DecryptLWEBool(eval.Lt(cts[i], cts[j])), "Lt(%v, %v)", m0, m1)
+			}
+		}
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				ct0, ct1 := cts[i], cts[j]
+				assert.Equal(t, m0 == m1, enc.DecryptLWEBool(eval.Eq(ct0, ct1)), "Eq(%v, %v)", m0, m1)
+				assert.Equal(t, m0 != m1, enc.DecryptLWEBool(eval.Ne(ct0, ct1)), "Ne(%v, %v)", m0, m1)
+				assert.Equal(t, m0 <= m1, enc.DecryptLWEBool(eval.Le(ct0, ct1)), "Le(%v, %v)", m0, m1)
+				assert.Equal(t, m0 > m1, enc.DecryptLWEBool(eval.Gt(ct0, ct1)), "Gt(%v, %v)", m0, m1)
+				assert.Equal(t, m0 >= m1, enc.DecryptLWEBool(eval.Ge(ct0, ct1)), "Ge(%v, %v)", m0, m1)
+			}
+		}
+	})
+
+	t.Run("MinMax", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				ct0, ct1 := cts[i], cts[j]
+				minOut, maxOut := m0, m1
+				if m0 > m1 {
+					minOut, maxOut = m1, m0
+				}
+				assert.Equal(t, minOut, enc.DecryptInteger(eval.Min(ct0, ct1)), "Min(%v, %v)", m0, m1)
+				assert.Equal(t, maxOut, enc.DecryptInteger(eval.Max(ct0, ct1)), "Max(%v, %v)", m0, m1)
+			}
+		}
+	})
+
+	t.Run("Bitwise", func(t *testing.T) {
+		for i, m0 := range messages {
+			for j, m1 := range messages {
+				ct0, ct1 := cts[i], cts[j]
+				assert.Equal(t, m0&m1, enc.DecryptInteger(eval.And(ct0, ct1)), "And(%v, %v)", m0, m1)
+				assert.Equal(t, m0|m1, enc.DecryptInteger(eval.Or(ct0, ct1)), "Or(%v, %v)", m0, m1)
+				assert.Equal(t, m0^m1, enc.DecryptInteger(eval.Xor(ct0, ct1)), "Xor(%v, %v)", m0, m1)
+			}
+		}
+		for i, m := range messages {
+			assert.Equal(t, ^m&mask, enc.DecryptInteger(eval.Not(cts[i])), "Not(%v)", m)
+		}
+	})
+
+	t.Run("Aliasing", func(t *testing.T) {
+		ct0, ct1 := cts[3].Copy(), cts[2].Copy()
+
+		sum := (3 + 2) & mask
+		eval.AddAssign(ct0, ct1, ct0)
+		assert.Equal(t, sum, enc.DecryptInteger(ct0))
+
+		maxOut := num.Max(sum, 2)
+		eval.MaxAssign(ct0, ct1, ct1)
+		assert.Equal(t, maxOut, enc.DecryptInteger(ct1))
+
+		eval.ScalarMulAssign(ct1, 3, ct1)
+		assert.Equal(t, (3*maxOut)&mask, enc.DecryptInteger(ct1))
+	})
+}
+
+func TestEvaluatorParams5(t *testing.T) {
+	params := integer.NewParameters(tfhe.Params5.Compile(), 8)
+	enc := integer.NewEncryptor(params)
+	eval := integer.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+	m0, m1 := uint64(rand.Intn(256)), uint64(rand.Intn(256))
+	ct0, ct1 := enc.EncryptInteger(m0), enc.EncryptInteger(m1)
+
+	assert.Equal(t, (m0+m1)%256, enc.DecryptInteger(eval.Add(ct0, ct1)), "Add(%v, %v)", m0, m1)
+	assert.Equal(t, (m0-m1)%256, enc.DecryptInteger(eval.Sub(ct0, ct1)), "Sub(%v, %v)", m0, m1)
+	assert.Equal(t, m0 < m1, enc.DecryptLWEBool(eval.Lt(ct0, ct1)), "Lt(%v, %v)", m0, m1)
+}
+
+func TestEvaluatorRandom(t *testing.T) {
+	for _, bits := range []int{16, 32, 64} {
+		t.Run(fmt.Sprintf("Bits=%v", bits), func(t *testing.T) {
+			if testing.Short() && bits > 16 {
+				t.Skip("random evaluator test over 16 bits skipped in short mode")
+			}
+			testEvaluatorRandom(t, integer.NewParameters(tfhe.Params5.Compile(), bits))
+		})
+	}
+}
+
+// testEvaluatorRandom checks every operation of the evaluator
+// on random messages against the native uint64 arithmetic.
+func testEvaluatorRandom[T tfhe.TorusInt](t *testing.T, params integer.Parameters[T]) {
+	enc := integer.NewEncryptor(params)
+	eval := integer.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+	mask := uint64(1<<params.Bits() - 1)
+	m0, m1, c := rand.Uint64()&mask, rand.Uint64()&mask, rand.Uint64()&mask
+	ct0, ct1 := enc.EncryptInteger(m0), enc.EncryptInteger(m1)
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		assert.Equal(t, (m0+m1)&mask, enc.DecryptInteger(eval.Add(ct0, ct1)), "Add(%v, %v)", m0, m1)
+		assert.Equal(t, (m0-m1)&mask, enc.DecryptInteger(eval.Sub(ct0, ct1)), "Sub(%v, %v)", m0, m1)
+		assert.Equal(t, (-m0)&mask, enc.DecryptInteger(eval.Neg(ct0)), "Neg(%v)", m0)
+		assert.Equal(t, (m0*c)&mask, enc.DecryptInteger(eval.ScalarMul(ct0, c)), "ScalarMul(%v, %v)", m0, c)
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		assert.Equal(t, m0 == m1, enc.DecryptLWEBool(eval.Eq(ct0, ct1)), "Eq(%v, %v)", m0, m1)
+		assert.Equal(t, m0 < m1, enc.DecryptLWEBool(eval.Lt(ct0, ct1)), "Lt(%v, %v)", m0, m1)
+		assert.Equal(t, m0 >= m1, enc.DecryptLWEBool(eval.Ge(ct0, ct1)), "Ge(%v, %v)", m0, m1)
+		assert.True(t, enc.DecryptLWEBool(eval.Eq(ct0, ct0)), "Eq(%v, %v)", m0, m0)
+	})
+
+	t.Run("MinMax", func(t *testing.T) {
+		minOut, maxOut := m0, m1
+		if m0 > m1 {
+			minOut, maxOut = m1, m0
+		}
+		assert.Equal(t, minOut, enc.DecryptInteger(eval.Min(ct0, ct1)), "Min(%v, %v)", m0, m1)
+		assert.Equal(t, maxOut, enc.DecryptInteger(eval.Max(ct0, ct1)), "Max(%v, %v)", m0, m1)
+	})
+
+	t.Run("Bitwise", func(t *testing.T) {
+		assert.Equal(t, m0&m1, enc.DecryptInteger(eval.And(ct0, ct1)), "And(%v, %v)", m0, m1)
+		assert.Equal(t, m0|m1, enc.DecryptInteger(eval.Or(ct0, ct1)), "Or(%v, %v)", m0, m1)
+		assert.Equal(t, m0^m1, enc.DecryptInteger(eval.Xor(ct0, ct1)), "Xor(%v, %v)", m0, m1)
+		assert.Equal(t, ^m0&mask, enc.DecryptInteger(eval.Not(ct0)), "Not(%v)", m0)
+	})
+}
+
+func BenchmarkEvaluator(b *testing.B) {
+	for _, bits := range []int{8, 16} {
+		params := integer.NewParameters(tfhe.Params6.Compile(), bits)
+		enc := integer.NewEncryptor(params)
+		eval := integer.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+		ct0 := enc.EncryptInteger(rand.Uint64())
+		ct1 := enc.EncryptInteger(rand.Uint64())
+		ctOut := integer.NewCiphertext(params)
+
+		b.Run(fmt.Sprintf("Add/Bits=%v", bits), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				eval.AddAssign(ct0, ct1, ctOut)
+			}
+		})
+
+		b.Run(fmt.Sprintf("Lt/Bits=%v", bits), func(b *testing.B) {
+			ctBool := tfhe.NewLWECiphertext(params.BaseParameters())
+			for i := 0; i < b.N; i++ {
+				eval.LtAssign(ct0, ct1, ctBool)
+			}
+		})
+	}
+}
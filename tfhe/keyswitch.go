@@ -64,3 +64,69 @@ func (e *Evaluator[T]) KeySwitchGLWEAssign(ct GLWECiphertext[T], ksk GLWEKeySwit
 		e.PolyEvaluator.ToPolyAssignUnsafe(e.buffer.ctProdFourierGLWE.Value[i+1], ctOut.Value[i+1])
 	}
 }
+
+// PackLWEToGLWE packs LWE ciphertexts to a GLWE ciphertext,
+// so that the i-th coefficient of the output encrypts the message of cts[i].
+// This is the public functional keyswitching, where the function places each message to its coefficient.
+//
+// Input ciphertexts should be of length ksk.InputLWEDimension + 1,
+// and the output ciphertext is encrypted with GLWEKey.
+//
+// Panics when len(cts) is larger than PolyDegree,
+// or when the length of any input ciphertext is not ksk.InputLWEDimension + 1.
+func (e *Evaluator[T]) PackLWEToGLWE(cts []LWECiphertext[T], ksk PackingKeySwitchKey[T]) GLWECiphertext[T] {
+	ctOut := NewGLWECiphertext(e.Parameters)
+	e.PackLWEToGLWEAssign(cts, ksk, ctOut)
+	return ctOut
+}
+
+// PackLWEToGLWEAssign packs LWE ciphertexts to a GLWE ciphertext and writes it to ctOut,
+// so that the i-th coefficient of ctOut encrypts the message of cts[i].
+// This is the public functional keyswitching, where the function places each message to its coefficient.
+//
+// Input ciphertexts should be of length ksk.InputLWEDimension + 1,
+// and the output ciphertext is encrypted with GLWEKey.
+//
+// Panics when len(cts) is larger than PolyDegree,
+// or when the length of any input ciphertext is not ksk.InputLWEDimension + 1.
+func (e *Evaluator[T]) PackLWEToGLWEAssign(cts []LWECiphertext[T], ksk PackingKeySwitchKey[T], ctOut GLWECiphertext[T]) {
+	if len(cts) > e.Parameters.polyDegree {
+		panic("LWE ciphertexts length larger than PolyDegree")
+	}
+	for j := range cts {
+		if len(cts[j].Value) != ksk.InputLWEDimension()+1 {
+			panic("LWE ciphertext dimension not equal to InputLWEDimension")
+		}
+	}
+
+	if e.buffer.pPack.Coeffs == nil {
+		e.buffer.pPack = e.PolyEvaluator.NewPoly()
+	}
+
+	fourierDecomposed := e.Decomposer.PolyFourierDecomposedBuffer(ksk.GadgetParameters)
+
+	for i := 0; i < ksk.InputLWEDimension(); i++ {
+		e.buffer.pPack.Clear()
+		for j := range cts {
+			e.buffer.pPack.Coeffs[j] = cts[j].Value[i+1]
+		}
+
+		e.Decomposer.FourierDecomposePolyAssign(e.buffer.pPack, ksk.GadgetParameters, fourierDecomposed)
+		for j := 0; j < ksk.GadgetParameters.level; j++ {
+			if i == 0 && j == 0 {
+				e.FourierPolyMulFourierGLWEAssign(ksk.Value[i].Value[j], fourierDecomposed[j], e.buffer.ctProdFourierGLWE)
+			} else {
+				e.FourierPolyMulAddFourierGLWEAssign(ksk.Value[i].Value[j], fourierDecomposed[j], e.buffer.ctProdFourierGLWE)
+			}
+		}
+	}
+
+	ctOut.Value[0].Clear()
+	for j := range cts {
+		ctOut.Value[0].Coeffs[j] = cts[j].Value[0]
+	}
+	e.PolyEvaluator.ToPolyAddAssignUnsafe(e.buffer.ctProdFourierGLWE.Value[0], ctOut.Value[0])
+	for i := 0; i < e.Parameters.glweRank; i++ {
+		e.PolyEvaluator.ToPolyAssignUnsafe(e.buffer.ctProdFourierGLWE.Value[i+1], ctOut.Value[i+1])
+	}
+}
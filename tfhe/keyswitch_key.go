@@ -91,6 +91,20 @@ func NewGLWEKeySwitchKeyCustom[T TorusInt](inputGLWERank, outputGLWERank, polyDe
 	return GLWEKeySwitchKey[T]{Value: ksk, GadgetParameters: gadgetParams}
 }
 
+// NewGLWEKeySwitchKeyForBootstrap creates a new GLWEKeySwitchingKey for bootstrapping,
+// used with KeySwitchMethodGLWE.
+func NewGLWEKeySwitchKeyForBootstrap[T TorusInt](params Parameters[T]) GLWEKeySwitchKey[T] {
+	return NewGLWEKeySwitchKeyForBootstrapCustom(params.lweDimension, params.glweRank, params.polyDegree, params.keySwitchParameters)
+}
+
+// NewGLWEKeySwitchKeyForBootstrapCustom creates a new GLWEKeySwitchingKey for bootstrapping with custom parameters.
+// The last GLWERank * PolyDegree - LWEDimension entries of LWELargeKey are packed to polynomials,
+// so the input GLWERank is their count divided by PolyDegree, rounded up.
+func NewGLWEKeySwitchKeyForBootstrapCustom[T TorusInt](lweDimension, glweRank, polyDegree int, gadgetParams GadgetParameters[T]) GLWEKeySwitchKey[T] {
+	inputGLWERank := (glweRank*polyDegree - lweDimension + polyDegree - 1) / polyDegree
+	return NewGLWEKeySwitchKeyCustom(inputGLWERank, glweRank, polyDegree, gadgetParams)
+}
+
 // InputGLWERank returns the input GLWERank of this key.
 func (ksk GLWEKeySwitchKey[T]) InputGLWERank() int {
 	return len(ksk.Value)
@@ -119,3 +133,60 @@ func (ksk *GLWEKeySwitchKey[T]) Clear() {
 		ksk.Value[i].Clear()
 	}
 }
+
+// PackingKeySwitchKey is a keyswitch key from LWEKey to GLWEKey,
+// used for packing LWE ciphertexts to a GLWE ciphertext.
+// Value[i] encrypts the constant polynomial of the i-th entry of the input LWE key.
+type PackingKeySwitchKey[T TorusInt] struct {
+	GadgetParameters GadgetParameters[T]
+
+	// Value has length InputLWEDimension.
+	Value []FourierGLevCiphertext[T]
+}
+
+// NewPackingKeySwitchKey creates a new PackingKeySwitchKey.
+func NewPackingKeySwitchKey[T TorusInt](params Parameters[T], inputDimension int, gadgetParams GadgetParameters[T]) PackingKeySwitchKey[T] {
+	ksk := make([]FourierGLevCiphertext[T], inputDimension)
+	for i := 0; i < inputDimension; i++ {
+		ksk[i] = NewFourierGLevCiphertext(params, gadgetParams)
+	}
+	return PackingKeySwitchKey[T]{Value: ksk, GadgetParameters: gadgetParams}
+}
+
+// NewPackingKeySwitchKeyCustom creates a new PackingKeySwitchKey with custom parameters.
+func NewPackingKeySwitchKeyCustom[T TorusInt](inputDimension, glweRank, polyDegree int, gadgetParams GadgetParameters[T]) PackingKeySwitchKey[T] {
+	ksk := make([]FourierGLevCiphertext[T], inputDimension)
+	for i := 0; i < inputDimension; i++ {
+		ksk[i] = NewFourierGLevCiphertextCustom(glweRank, polyDegree, gadgetParams)
+	}
+	return PackingKeySwitchKey[T]{Value: ksk, GadgetParameters: gadgetParams}
+}
+
+// InputLWEDimension returns the input LWEDimension of this key.
+func (ksk PackingKeySwitchKey[T]) InputLWEDimension() int {
+	return len(ksk.Value)
+}
+
+// Copy returns a copy of the key.
+func (ksk PackingKeySwitchKey[T]) Copy() PackingKeySwitchKey[T] {
+	kskCopy := make([]FourierGLevCiphertext[T], len(ksk.Value))
+	for i := range ksk.Value {
+		kskCopy[i] = ksk.Value[i].Copy()
+	}
+	return PackingKeySwitchKey[T]{Value: kskCopy, GadgetParameters: ksk.GadgetParameters}
+}
+
+// CopyFrom copies values from key.
+func (ksk *PackingKeySwitchKey[T]) CopyFrom(kskIn PackingKeySwitchKey[T]) {
+	for i := range ksk.Value {
+		ksk.Value[i].CopyFrom(kskIn.Value[i])
+	}
+	ksk.GadgetParameters = kskIn.GadgetParameters
+}
+
+// Clear clears the key.
+func (ksk *PackingKeySwitchKey[T]) Clear() {
+	for i := range ksk.Value {
+		ksk.Value[i].Clear()
+	}
+}
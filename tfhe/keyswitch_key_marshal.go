@@ -366,3 +366,199 @@ func (ksk *GLWEKeySwitchKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
 
 	return
 }
+
+// ByteSize returns the size of the key in bytes.
+func (ksk PackingKeySwitchKey[T]) ByteSize() int {
+	inputDimension := len(ksk.Value)
+	level := len(ksk.Value[0].Value)
+	glweRank := len(ksk.Value[0].Value[0].Value) - 1
+	polyDegree := ksk.Value[0].Value[0].Value[0].Degree()
+
+	return 40 + inputDimension*level*(glweRank+1)*polyDegree*8
+}
+
+// headerWriteTo writes the header.
+func (ksk PackingKeySwitchKey[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var buf [8]byte
+
+	base := ksk.GadgetParameters.base
+	binary.BigEndian.PutUint64(buf[:], uint64(base))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	level := ksk.GadgetParameters.level
+	binary.BigEndian.PutUint64(buf[:], uint64(level))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	inputDimension := len(ksk.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(inputDimension))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	glweRank := len(ksk.Value[0].Value[0].Value) - 1
+	binary.BigEndian.PutUint64(buf[:], uint64(glweRank))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	polyDegree := ksk.Value[0].Value[0].Value[0].Degree()
+	binary.BigEndian.PutUint64(buf[:], uint64(polyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (ksk PackingKeySwitchKey[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	polyDegree := ksk.Value[0].Value[0].Value[0].Degree()
+	buf := make([]byte, polyDegree*8)
+
+	for i := range ksk.Value {
+		for j := range ksk.Value[i].Value {
+			for k := range ksk.Value[i].Value[j].Value {
+				if nWrite, err = floatVecWriteToBuffered(ksk.Value[i].Value[j].Value[k].Coeffs, buf, w); err != nil {
+					return n + nWrite, err
+				}
+				n += nWrite
+			}
+		}
+	}
+
+	return
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] Base
+//	[8] Level
+//	[8] InputDimension
+//	[8] GLWERank
+//	[8] PolyDegree
+//	    Value
+func (ksk PackingKeySwitchKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = ksk.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = ksk.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(ksk.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+func (ksk *PackingKeySwitchKey[T]) headerReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	base := T(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	level := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	inputDimension := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	glweRank := int(binary.BigEndian.Uint64(buf[:]))
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	polyDegree := int(binary.BigEndian.Uint64(buf[:]))
+
+	*ksk = NewPackingKeySwitchKeyCustom(inputDimension, glweRank, polyDegree, GadgetParametersLiteral[T]{Base: base, Level: level}.Compile())
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (ksk *PackingKeySwitchKey[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	polyDegree := ksk.Value[0].Value[0].Value[0].Degree()
+	buf := make([]byte, polyDegree*8)
+
+	for i := range ksk.Value {
+		for j := range ksk.Value[i].Value {
+			for k := range ksk.Value[i].Value[j].Value {
+				if nRead, err = floatVecReadFromBuffered(ksk.Value[i].Value[j].Value[k].Coeffs, buf, r); err != nil {
+					return n + nRead, err
+				}
+				n += nRead
+			}
+		}
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (ksk *PackingKeySwitchKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = ksk.headerReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = ksk.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (ksk PackingKeySwitchKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, ksk.ByteSize()))
+	_, err = ksk.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (ksk *PackingKeySwitchKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := ksk.ReadFrom(buf)
+	return err
+}
@@ -21,3 +21,17 @@ func (e *Encryptor[T]) GenGLWEKeySwitchKey(skIn GLWESecretKey[T], gadgetParams G
 
 	return ksk
 }
+
+// GenPackingKeySwitchKey samples a new keyswitch key skIn -> GLWEKey,
+// used for packing LWE ciphertexts encrypted with skIn.
+func (e *Encryptor[T]) GenPackingKeySwitchKey(skIn LWESecretKey[T], gadgetParams GadgetParameters[T]) PackingKeySwitchKey[T] {
+	ksk := NewPackingKeySwitchKey(e.Parameters, len(skIn.Value), gadgetParams)
+
+	for i := 0; i < ksk.InputLWEDimension(); i++ {
+		e.buffer.ptGGSW.Clear()
+		e.buffer.ptGGSW.Coeffs[0] = skIn.Value[i]
+		e.EncryptFourierGLevPolyAssign(e.buffer.ptGGSW, ksk.Value[i])
+	}
+
+	return ksk
+}
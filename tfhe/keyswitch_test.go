@@ -0,0 +1,174 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/csprng"
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+// testKeySwitchForBootstrapNoise checks that KeySwitchForBootstrap of eval
+// switches LWELargeKey to LWEKey with the estimated error.
+// The error does not depend on the phase, so the inputs are uniformly random.
+func testKeySwitchForBootstrapNoise[T tfhe.TorusInt](t *testing.T, enc *tfhe.Encryptor[T], eval *tfhe.Evaluator[T]) {
+	s := csprng.NewUniformSampler[T]()
+	ct := tfhe.NewLWECiphertextCustom[T](eval.Parameters.GLWEDimension())
+
+	keySwitch := &noiseStage{name: "KeySwitch", estimate: eval.Parameters.EstimateKeySwitchForBootstrapStdDev()}
+	for i := 0; i < noiseFreshSampleCount; i++ {
+		s.SampleVecAssign(ct.Value)
+		ctOut := eval.KeySwitchForBootstrap(ct)
+		addNoise(keySwitch, lwePhase(ctOut, enc.SecretKey.LWEKey)-lwePhase(ct, enc.SecretKey.LWELargeKey))
+	}
+	keySwitch.check(t)
+}
+
+func TestKeySwitchGLWE(t *testing.T) {
+	paramsGLWE := tfhe.ParamsEBS5.WithKeySwitchMethod(tfhe.KeySwitchMethodGLWE).Compile()
+	encGLWE := tfhe.NewEncryptor(paramsGLWE)
+	evkGLWE := encGLWE.GenEvaluationKeyParallel()
+	evalGLWE := tfhe.NewEvaluator(paramsGLWE, evkGLWE)
+
+	t.Run("EvaluationKey", func(t *testing.T) {
+		assert.Empty(t, evkGLWE.KeySwitchKey.Value)
+		assert.Equal(t, 1, evkGLWE.GLWEKeySwitchKey.InputGLWERank())
+		assert.Equal(t, paramsGLWE.KeySwitchParameters(), evkGLWE.GLWEKeySwitchKey.GadgetParameters)
+
+		t.Logf("KeySwitchKey: GLWE=%vB LWE=%vB", evkGLWE.GLWEKeySwitchKey.ByteSize(), eval.EvaluationKey.KeySwitchKey.ByteSize())
+		assert.Less(t, evkGLWE.GLWEKeySwitchKey.ByteSize(), eval.EvaluationKey.KeySwitchKey.ByteSize())
+	})
+
+	t.Run("KeySwitch", func(t *testing.T) {
+		testKeySwitchForBootstrapNoise(t, encGLWE, evalGLWE)
+	})
+
+	t.Run("KeySwitch/Uint32", func(t *testing.T) {
+		// At depth one, the tail of LWELargeKey does not fill a polynomial.
+		params := tfhe.Params2Uint32.WithKeySwitchMethod(tfhe.KeySwitchMethodGLWE).Compile().AtDepth(1)
+		assert.NotZero(t, (params.GLWEDimension()-params.LWEDimension())%params.PolyDegree())
+
+		enc := tfhe.NewEncryptor(params)
+		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+		testKeySwitchForBootstrapNoise(t, enc, eval)
+	})
+
+	t.Run("Bootstrap", func(t *testing.T) {
+		f := func(x int) int { return 2*x + 5 }
+		decomposedLUT := evalGLWE.NewDecomposedLutEBS()
+		evalGLWE.GenLookUpTableNegDecomposedEBSAssign(f, paramsGLWE.MessageModulus(), paramsGLWE.Scale(), &decomposedLUT)
+		compressLUT := tfhe.NewLookUpTable(paramsGLWE)
+		evalGLWE.GenCompressLUTAssign(compressLUT)
+
+		messageModulus := int(paramsGLWE.MessageModulus())
+		for _, x := range []int{0, messageModulus/2 + 1, messageModulus - 1} {
+			ct := encGLWE.EncryptLWE(x)
+			evalGLWE.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedLUT, ct)
+			assert.Equal(t, f(x)%messageModulus, encGLWE.DecryptLWE(ct))
+		}
+	})
+
+	t.Run("Estimate", func(t *testing.T) {
+		// Both methods add the same error to the constant term.
+		assert.Equal(t, params.EstimateKeySwitchForBootstrapStdDev(), paramsGLWE.EstimateKeySwitchForBootstrapStdDev())
+		assert.Equal(t, params.NoiseBudget(tfhe.PipelineEBS), paramsGLWE.NoiseBudget(tfhe.PipelineEBS))
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		paramsData, err := paramsGLWE.MarshalBinary()
+		assert.NoError(t, err)
+
+		var paramsOut tfhe.Parameters[uint64]
+		assert.NoError(t, paramsOut.UnmarshalBinary(paramsData))
+		assert.Equal(t, paramsGLWE, paramsOut)
+		assert.NotEqual(t, params.Fingerprint(), paramsGLWE.Fingerprint())
+
+		ksk := encGLWE.GenGLWEKeySwitchKeyForBootstrap()
+		evk := tfhe.EvaluationKey[uint64]{
+			BlindRotateKey:   tfhe.NewBlindRotateKeyCustom(1, paramsGLWE.GLWERank(), paramsGLWE.PolyDegree(), paramsGLWE.BlindRotateParameters()),
+			KeySwitchKey:     tfhe.NewLWEKeySwitchKeyCustom(0, 0, paramsGLWE.KeySwitchParameters()),
+			GLWEKeySwitchKey: ksk,
+		}
+		evkData, err := evk.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, evk.ByteSize(), len(evkData))
+
+		var evkOut tfhe.EvaluationKey[uint64]
+		assert.NoError(t, evkOut.UnmarshalBinary(evkData))
+		assert.Equal(t, evk, evkOut)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { tfhe.ParamsEBS5.WithKeySwitchMethod(tfhe.KeySwitchMethod(-1)).Compile() })
+	})
+}
+
+func Benchmark_KeySwitchForBootstrap(b *testing.B) {
+	methods := []struct {
+		name   string
+		method tfhe.KeySwitchMethod
+	}{
+		{"LWE", tfhe.KeySwitchMethodLWE},
+		{"GLWE", tfhe.KeySwitchMethodGLWE},
+	}
+
+	for _, params := range paramsListEBS {
+		for _, m := range methods {
+			params := params.WithKeySwitchMethod(m.method).Compile()
+			enc := tfhe.NewEncryptor(params)
+			eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+			ct := enc.EncryptLWE(0)
+			ctOut := tfhe.NewLWECiphertextCustom[uint64](params.LWEDimension())
+
+			b.Run(fmt.Sprintf("prec=%v/method=%v", num.Log2(params.MessageModulus()), m.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					eval.KeySwitchForBootstrapAssign(ct, ctOut)
+				}
+			})
+		}
+	}
+}
+
+func TestPackLWEToGLWE(t *testing.T) {
+	gadgetParams := tfhe.GadgetParametersLiteral[uint64]{Base: 1 << 15, Level: 2}.Compile()
+	ksk := enc.GenPackingKeySwitchKey(enc.SecretKey.LWEKey, gadgetParams)
+
+	messageModulus := int(params.MessageModulus())
+	messages := make([]int, 2*messageModulus)
+	cts := make([]tfhe.LWECiphertext[uint64], len(messages))
+	for i := range messages {
+		messages[i] = (7 * i) % messageModulus
+		cts[i] = eval.KeySwitchForBootstrap(enc.EncryptLWE(messages[i]))
+	}
+
+	t.Run("Pack", func(t *testing.T) {
+		ctOut := eval.PackLWEToGLWE(cts, ksk)
+		messagesOut := enc.DecryptGLWE(ctOut)
+		assert.Equal(t, messages, messagesOut[:len(messages)])
+		assert.Equal(t, make([]int, params.PolyDegree()-len(messages)), messagesOut[len(messages):])
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		kskSmall := enc.GenPackingKeySwitchKey(tfhe.LWESecretKey[uint64]{Value: enc.SecretKey.LWEKey.Value[:4]}, gadgetParams)
+		data, err := kskSmall.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, kskSmall.ByteSize(), len(data))
+
+		var kskOut tfhe.PackingKeySwitchKey[uint64]
+		assert.NoError(t, kskOut.UnmarshalBinary(data))
+		assert.Equal(t, kskSmall, kskOut)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { eval.PackLWEToGLWE(make([]tfhe.LWECiphertext[uint64], params.PolyDegree()+1), ksk) })
+		assert.Panics(t, func() {
+			eval.PackLWEToGLWE([]tfhe.LWECiphertext[uint64]{tfhe.NewLWECiphertextCustom[uint64](ksk.InputLWEDimension() - 1)}, ksk)
+		})
+		assert.Panics(t, func() {
+			eval.PackLWEToGLWE([]tfhe.LWECiphertext[uint64]{tfhe.NewLWECiphertextCustom[uint64](ksk.InputLWEDimension() + 1)}, ksk)
+		})
+	})
+}
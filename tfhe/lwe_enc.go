@@ -1,6 +1,7 @@
 package tfhe
 
 import (
+	"github.com/sp301415/tfhe-go/math/csprng"
 	"github.com/sp301415/tfhe-go/math/num"
 	"github.com/sp301415/tfhe-go/math/vec"
 )
@@ -15,6 +16,18 @@ func (e *Encryptor[T]) EncryptLWEAssign(message int, ctOut LWECiphertext[T]) {
 	e.EncryptLWEPlaintextAssign(e.EncodeLWE(message), ctOut)
 }
 
+// EncryptLWESigned encodes and encrypts signed integer message to LWE ciphertext.
+// See [*Encoder.EncodeLWESigned] for details.
+func (e *Encryptor[T]) EncryptLWESigned(message int) LWECiphertext[T] {
+	return e.EncryptLWEPlaintext(e.EncodeLWESigned(message))
+}
+
+// EncryptLWESignedAssign encodes and encrypts signed integer message to LWE ciphertext and writes it to ctOut.
+// See [*Encoder.EncodeLWESigned] for details.
+func (e *Encryptor[T]) EncryptLWESignedAssign(message int, ctOut LWECiphertext[T]) {
+	e.EncryptLWEPlaintextAssign(e.EncodeLWESigned(message), ctOut)
+}
+
 // EncryptLWEPlaintext encrypts LWE plaintext to LWE ciphertext.
 func (e *Encryptor[T]) EncryptLWEPlaintext(pt LWEPlaintext[T]) LWECiphertext[T] {
 	ctOut := NewLWECiphertext(e.Parameters)
@@ -31,7 +44,12 @@ func (e *Encryptor[T]) EncryptLWEPlaintextAssign(pt LWEPlaintext[T], ctOut LWECi
 // EncryptLWEBody encrypts the value in the body of LWE ciphertext and overrides it.
 // This avoids the need for most buffers.
 func (e *Encryptor[T]) EncryptLWEBody(ct LWECiphertext[T]) {
-	e.UniformSampler.SampleVecAssign(ct.Value[1:])
+	e.encryptLWEBodyWithSampler(e.UniformSampler, ct)
+}
+
+// encryptLWEBodyWithSampler is [*Encryptor.EncryptLWEBody] where the mask is sampled from s.
+func (e *Encryptor[T]) encryptLWEBodyWithSampler(s *csprng.UniformSampler[T], ct LWECiphertext[T]) {
+	s.SampleVecAssign(ct.Value[1:])
 	ct.Value[0] += -vec.Dot(ct.Value[1:], e.DefaultLWESecretKey().Value)
 	ct.Value[0] += e.GaussianSampler.Sample(e.Parameters.DefaultLWEStdDevQ())
 }
@@ -41,6 +59,12 @@ func (e *Encryptor[T]) DecryptLWE(ct LWECiphertext[T]) int {
 	return e.DecodeLWE(e.DecryptLWEPhase(ct))
 }
 
+// DecryptLWESigned decrypts and decodes LWE ciphertext to signed integer message.
+// See [*Encoder.DecodeLWESigned] for details.
+func (e *Encryptor[T]) DecryptLWESigned(ct LWECiphertext[T]) int {
+	return e.DecodeLWESigned(e.DecryptLWEPhase(ct))
+}
+
 // DecryptLWEPhase decrypts LWE ciphertext to LWE plaintext including errors.
 func (e *Encryptor[T]) DecryptLWEPhase(ct LWECiphertext[T]) LWEPlaintext[T] {
 	ptOut := ct.Value[0] + vec.Dot(ct.Value[1:], e.DefaultLWESecretKey().Value)
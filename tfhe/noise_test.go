@@ -0,0 +1,264 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/math/vec"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// noiseRatioMin is the smallest allowed ratio of empirical standard deviation to the estimate.
+	noiseRatioMin = 0.5
+	// noiseRatioMax is the largest allowed ratio of empirical standard deviation to the estimate.
+	noiseRatioMax = 2.0
+
+	// noiseSampleCount is the number of bootstrappings for each pipeline.
+	noiseSampleCount = 32
+	// noiseFreshSampleCount is the number of samples for the stages without blind rotation.
+	noiseFreshSampleCount = 256
+)
+
+// noiseStage collects errors of a stage of bootstrapping,
+// to compare their standard deviation with the estimate.
+type noiseStage struct {
+	name     string
+	estimate float64
+	errors   []float64
+}
+
+// addNoise adds the error x to s, where x is interpreted as a signed value in [-Q/2, Q/2).
+func addNoise[T tfhe.TorusInt](s *noiseStage, x T) {
+	if x >= T(1)<<(num.SizeT[T]()-1) {
+		s.errors = append(s.errors, -float64(-x))
+		return
+	}
+	s.errors = append(s.errors, float64(x))
+}
+
+// stdDev returns the empirical standard deviation of the errors.
+// Errors are assumed to have zero mean, so any bias is counted as error.
+func (s *noiseStage) stdDev() float64 {
+	variance := 0.0
+	for _, e := range s.errors {
+		variance += e * e
+	}
+	return math.Sqrt(variance / float64(len(s.errors)))
+}
+
+// check reports the empirical standard deviation next to the estimate,
+// and fails if their ratio is not in [noiseRatioMin, noiseRatioMax].
+func (s *noiseStage) check(t *testing.T) {
+	stdDev := s.stdDev()
+	ratio := stdDev / s.estimate
+	t.Logf("%-12s samples=%-6d log2(empirical)=%6.2f log2(estimate)=%6.2f ratio=%.3f",
+		s.name, len(s.errors), math.Log2(stdDev), math.Log2(s.estimate), ratio)
+	assert.GreaterOrEqual(t, ratio, noiseRatioMin, s.name)
+	assert.LessOrEqual(t, ratio, noiseRatioMax, s.name)
+}
+
+// lwePhase returns the phase of ct under key.
+func lwePhase[T tfhe.TorusInt](ct tfhe.LWECiphertext[T], key tfhe.LWESecretKey[T]) T {
+	return ct.Value[0] + vec.Dot(ct.Value[1:], key.Value)
+}
+
+// modSwitchPhase returns the phase of ct under key after modulus switching,
+// where modSwitch switches the modulus from Q to 2^logModulus.
+// The output is in [0, 2^logModulus).
+func modSwitchPhase[T tfhe.TorusInt](ct tfhe.LWECiphertext[T], key tfhe.LWESecretKey[T], modSwitch func(T) int, logModulus int) T {
+	phase := T(modSwitch(ct.Value[0]))
+	for i := range key.Value {
+		phase += T(modSwitch(ct.Value[i+1])) * key.Value[i]
+	}
+	return phase & (T(1)<<logModulus - 1)
+}
+
+func TestNoiseBudget(t *testing.T) {
+	pipelines := []tfhe.Pipeline{tfhe.PipelineClassic, tfhe.PipelineFDFBCompress, tfhe.PipelineEBS, tfhe.PipelineHierarchical}
+
+	for name, params := range map[string]tfhe.ParametersLiteral[uint64]{"Params5": tfhe.Params5, "ParamsEBS5": tfhe.ParamsEBS5} {
+		params := params.Compile()
+		for _, pipeline := range pipelines {
+			t.Run(fmt.Sprintf("Breakdown/%v/%v", name, pipeline), func(t *testing.T) {
+				budget := params.NoiseBudget(pipeline)
+				assert.Equal(t, pipeline, budget.Pipeline)
+
+				if pipeline == tfhe.PipelineHierarchical {
+					assert.Len(t, budget.BlindRotateVariance, params.HierarchyDepth())
+				} else {
+					assert.Len(t, budget.BlindRotateVariance, 1)
+				}
+
+				outputVariance := budget.BlindRotateVarianceSum()
+				if params.BootstrapOrder() == tfhe.OrderBlindRotateKeySwitch {
+					outputVariance += budget.KeySwitchVariance
+				}
+				assert.Equal(t, outputVariance, budget.OutputVariance)
+				assert.InEpsilon(t, budget.ModSwitchVariance+budget.BlindRotateVarianceSum()+budget.KeySwitchVariance, budget.MaxErrorVariance, 1e-9)
+			})
+		}
+	}
+
+	t.Run("Estimators", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+
+		classic := params.NoiseBudget(tfhe.PipelineClassic)
+		assert.InEpsilon(t, params.EstimateMaxErrorStdDev(), math.Sqrt(classic.MaxErrorVariance), 1e-9)
+		assert.InEpsilon(t, math.Log2(params.EstimateFailureProbability()), classic.LogFailureProbability, 1e-9)
+		assert.InEpsilon(t, params.EstimateModSwitchStdDev(), math.Sqrt(classic.ModSwitchVariance), 1e-9)
+
+		// FDFBCompress has the same error as Classic, but bootstraps twice.
+		compress := params.NoiseBudget(tfhe.PipelineFDFBCompress)
+		assert.Equal(t, classic.MaxErrorVariance, compress.MaxErrorVariance)
+		assert.GreaterOrEqual(t, compress.LogFailureProbability, classic.LogFailureProbability)
+
+		ebs := params.NoiseBudget(tfhe.PipelineEBS)
+		assert.InEpsilon(t, params.EstimateMaxErrorStdDevNewEBS(), math.Sqrt(ebs.MaxErrorVariance), 1e-9)
+		assert.InEpsilon(t, math.Log2(params.EstimateFailureProbabilityNewFDFB_EBS()), ebs.LogFailureProbability, 1e-9)
+
+		hierarchical := params.NoiseBudget(tfhe.PipelineHierarchical)
+		assert.InEpsilon(t, params.EstimateMaxErrorStdDevNew(), math.Sqrt(hierarchical.MaxErrorVariance), 1e-9)
+		assert.InEpsilon(t, params.EstimateBlindRotateStdDevNew(), math.Sqrt(hierarchical.BlindRotateVarianceSum()), 1e-9)
+		assert.InEpsilon(t, params.EstimateKeySwitchForBootstrapStdDevNew(), math.Sqrt(hierarchical.KeySwitchVariance), 1e-9)
+		assert.InEpsilon(t, math.Log2(params.EstimateFailureProbabilityNewFDFB()), hierarchical.LogFailureProbability, 1e-9)
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() { tfhe.Params5.Compile().NoiseBudget(tfhe.Pipeline(-1)) })
+		assert.Equal(t, "Unknown", tfhe.Pipeline(-1).String())
+	})
+}
+
+func TestNoise(t *testing.T) {
+	// Blind rotation of a sparse LUT has smaller error than the estimate,
+	// so we use a function with dense decomposed LUTs.
+	f := func(x int) int { return 7*x*x + 3*x + 11 }
+
+	t.Run("Bootstrap", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		enc := tfhe.NewEncryptor(params)
+		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+		messageModulus := int(params.MessageModulus())
+		logLUTSize := num.Log2(params.LookUpTableSize())
+
+		modSwitch := &noiseStage{name: "ModSwitch", estimate: params.EstimateModSwitchStdDev()}
+		modSwitchNew := &noiseStage{name: "ModSwitchNew", estimate: params.EstimateModSwitchNewStdDev()}
+		for i := 0; i < noiseFreshSampleCount; i++ {
+			ct := enc.EncryptLWE(i % messageModulus)
+			phase := lwePhase(ct, enc.DefaultLWESecretKey())
+			addNoise(modSwitch, modSwitchPhase(ct, enc.DefaultLWESecretKey(), eval.ModSwitchOriginal, logLUTSize+1)<<(64-logLUTSize-1)-phase)
+			addNoise(modSwitchNew, modSwitchPhase(ct, enc.DefaultLWESecretKey(), eval.ModSwitch, logLUTSize)<<(64-logLUTSize)-phase)
+		}
+		modSwitch.check(t)
+		modSwitchNew.check(t)
+
+		// Every coefficient of the blind rotation output has the same error distribution,
+		// so the output is compared with the rotated LUT.
+		lut := eval.GenLookUpTable(func(x int) int { return x })
+		blindRotate := &noiseStage{name: "BlindRotate", estimate: params.EstimateBlindRotateStdDev()}
+		keySwitch := &noiseStage{name: "KeySwitch", estimate: params.EstimateKeySwitchForBootstrapStdDev()}
+		bootstrap := &noiseStage{name: "Bootstrap", estimate: math.Sqrt(params.NoiseBudget(tfhe.PipelineClassic).OutputVariance)}
+		ctExtract := tfhe.NewLWECiphertextCustom[uint64](params.GLWEDimension())
+		for i := 0; i < noiseSampleCount; i++ {
+			ct := enc.EncryptLWE(i % (messageModulus / 2))
+
+			rotate := modSwitchPhase(ct, enc.DefaultLWESecretKey(), eval.ModSwitchOriginal, logLUTSize+1)
+			lutRotated := eval.PolyEvaluator.MonomialMulPoly(lut.Value[0], -int(rotate))
+
+			if i < noiseSampleCount/8 {
+				ctRotate := eval.BlindRotate(ct, lut)
+				pt := enc.DecryptGLWEPhase(ctRotate)
+				for j := 0; j < params.PolyDegree(); j++ {
+					addNoise(blindRotate, pt.Value.Coeffs[j]-lutRotated.Coeffs[j])
+				}
+
+				for j := 0; j < noiseFreshSampleCount/(noiseSampleCount/8); j++ {
+					ctRotate.ToLWECiphertextAssign(j, ctExtract)
+					ctOut := eval.KeySwitchForBootstrap(ctExtract)
+					addNoise(keySwitch, lwePhase(ctOut, enc.SecretKey.LWEKey)-lwePhase(ctExtract, enc.SecretKey.LWELargeKey))
+				}
+			}
+
+			ctOut := eval.BootstrapLUT(ct, lut)
+			addNoise(bootstrap, enc.DecryptLWEPhase(ctOut).Value-lutRotated.Coeffs[0])
+		}
+		blindRotate.check(t)
+		keySwitch.check(t)
+		bootstrap.check(t)
+	})
+
+	t.Run("FDFB", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		enc := tfhe.NewEncryptor(params)
+		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+		messageModulus := int(params.MessageModulus())
+
+		compressLUT := tfhe.NewLookUpTable(params)
+		eval.GenExtendedCompressLUTAssign(compressLUT)
+		fdfbLUT := tfhe.NewLookUpTable(params)
+		eval.GenExtendedFDFBLookUpTableAssign(f, fdfbLUT)
+
+		bootstrap := &noiseStage{name: "FDFB", estimate: math.Sqrt(params.NoiseBudget(tfhe.PipelineFDFBCompress).OutputVariance)}
+		ctOut := tfhe.NewLWECiphertext(params)
+		for i := 0; i < noiseSampleCount; i++ {
+			x := i % messageModulus
+			eval.FDFBLUTAssign(enc.EncryptLWE(x), compressLUT, fdfbLUT, ctOut)
+			addNoise(bootstrap, enc.DecryptLWEPhase(ctOut).Value-enc.EncodeLWE(f(x)).Value)
+		}
+		bootstrap.check(t)
+	})
+
+	t.Run("HierarchicalFDFB", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		eval := tfhe.NewFullDomainEvaluator(params, tfhe.GenHierarchicalEvaluationKeyParallel(params, enc))
+
+		messageModulus := int(params.MessageModulus())
+		lut := eval.GenDecomposedLUT(f)
+
+		bootstrap := &noiseStage{name: "FDFB", estimate: math.Sqrt(params.NoiseBudget(tfhe.PipelineHierarchical).OutputVariance)}
+		ctOut := tfhe.NewLWECiphertext(params)
+		for i := 0; i < noiseSampleCount; i++ {
+			x := i % messageModulus
+			eval.BootstrapLUTAssign(enc[0].EncryptLWE(x), lut, ctOut)
+			addNoise(bootstrap, enc[0].DecryptLWEPhase(ctOut).Value-enc[0].EncodeLWE(f(x)).Value)
+		}
+		bootstrap.check(t)
+	})
+
+	t.Run("EBSFDFB", func(t *testing.T) {
+		params := tfhe.ParamsEBS5.Compile()
+		enc := tfhe.NewEncryptor(params)
+		eval := tfhe.NewEvaluator(params, enc.GenEvaluationKeyParallel())
+
+		messageModulus := int(params.MessageModulus())
+
+		keySwitch := &noiseStage{name: "KeySwitch", estimate: params.EstimateKeySwitchForBootstrapStdDev()}
+		for i := 0; i < noiseFreshSampleCount; i++ {
+			ct := enc.EncryptLWE(i % messageModulus)
+			ctOut := eval.KeySwitchForBootstrap(ct)
+			addNoise(keySwitch, lwePhase(ctOut, enc.SecretKey.LWEKey)-lwePhase(ct, enc.SecretKey.LWELargeKey))
+		}
+		keySwitch.check(t)
+
+		lut := eval.NewDecomposedLutEBS()
+		eval.GenLookUpTableNegDecomposedEBSAssign(f, params.MessageModulus(), params.Scale(), &lut)
+		compressLUT := tfhe.NewLookUpTable(params)
+		eval.GenCompressLUTAssign(compressLUT)
+
+		bootstrap := &noiseStage{name: "FDFB", estimate: math.Sqrt(params.NoiseBudget(tfhe.PipelineEBS).OutputVariance)}
+		ctOut := tfhe.NewLWECiphertext(params)
+		for i := 0; i < noiseSampleCount; i++ {
+			x := i % messageModulus
+			eval.BootstrapExtendedFullDomainAssignNew(enc.EncryptLWE(x), compressLUT, lut, ctOut)
+			addNoise(bootstrap, enc.DecryptLWEPhase(ctOut).Value-enc.EncodeLWE(f(x)).Value)
+		}
+		bootstrap.check(t)
+	})
+}
@@ -3,9 +3,10 @@ package tfhe
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"hash/fnv"
 	"io"
 	"math"
-	"math/bits"
 
 	"github.com/sp301415/tfhe-go/math/num"
 )
@@ -225,6 +226,22 @@ const (
 	OrderBlindRotateKeySwitch
 )
 
+// KeySwitchMethod is an enum type for the method of keyswitching for bootstrapping.
+type KeySwitchMethod int
+
+const (
+	// KeySwitchMethodLWE performs the keyswitching for bootstrapping
+	// with scalar products over LWE keyswitching keys.
+	KeySwitchMethodLWE KeySwitchMethod = iota
+
+	// KeySwitchMethodGLWE performs the keyswitching for bootstrapping
+	// with polynomial products over GLWE keyswitching keys.
+	// The input mask is packed to polynomials of degree PolyDegree,
+	// so that the keyswitching key is stored in the fourier domain
+	// and the keyswitching runs in O(LWEDimension * PolyDegree * log(PolyDegree)) time.
+	KeySwitchMethodGLWE
+)
+
 // ParametersLiteral is a structure for TFHE parameters.
 //
 // # Warning
@@ -247,6 +264,14 @@ type ParametersLiteral[T TorusInt] struct {
 	//
 	// If zero, then it is set to PolyDegree.
 	LookUpTableSize int
+	// BasePolyDegree is the degree of the base ring used in full-domain functional bootstrapping.
+	//
+	// The recursive LUT decomposition halves the lookup table until it reaches BasePolyDegree,
+	// and the hierarchical evaluators halve PolyDegree until it reaches BasePolyDegree.
+	// Therefore, it must be a power of two not larger than PolyDegree.
+	//
+	// If zero, then it is set to PolyDegree.
+	BasePolyDegree int
 
 	// LWEStdDev is the normalized standard deviation used for gaussian error sampling in LWE encryption.
 	LWEStdDev float64
@@ -290,6 +315,13 @@ type ParametersLiteral[T TorusInt] struct {
 	//
 	// If zero, then it is set to OrderKeySwitchBlindRotate.
 	BootstrapOrder BootstrapOrder
+
+	// KeySwitchMethod is the method of keyswitching for bootstrapping.
+	// If this is set to KeySwitchMethodGLWE, the evaluation key holds
+	// a GLWEKeySwitchKey instead of a LWEKeySwitchKey.
+	//
+	// If zero, then it is set to KeySwitchMethodLWE.
+	KeySwitchMethod KeySwitchMethod
 }
 
 // WithLWEDimension sets the LWEDimension and returns the new ParametersLiteral.
@@ -316,6 +348,12 @@ func (p ParametersLiteral[T]) WithLookUpTableSize(lookUpTableSize int) Parameter
 	return p
 }
 
+// WithBasePolyDegree sets the BasePolyDegree and returns the new ParametersLiteral.
+func (p ParametersLiteral[T]) WithBasePolyDegree(basePolyDegree int) ParametersLiteral[T] {
+	p.BasePolyDegree = basePolyDegree
+	return p
+}
+
 // WithLWEStdDev sets the LWEStdDev and returns the new ParametersLiteral.
 func (p ParametersLiteral[T]) WithLWEStdDev(lweStdDev float64) ParametersLiteral[T] {
 	p.LWEStdDev = lweStdDev
@@ -358,6 +396,12 @@ func (p ParametersLiteral[T]) WithBootstrapOrder(bootstrapOrder BootstrapOrder)
 	return p
 }
 
+// WithKeySwitchMethod sets the KeySwitchMethod and returns the new ParametersLiteral.
+func (p ParametersLiteral[T]) WithKeySwitchMethod(keySwitchMethod KeySwitchMethod) ParametersLiteral[T] {
+	p.KeySwitchMethod = keySwitchMethod
+	return p
+}
+
 // Compile transforms ParametersLiteral to read-only Parameters.
 // If there is any invalid parameter in the literal, it panics.
 // Default parameters are guaranteed to be compiled without panics.
@@ -372,6 +416,9 @@ func (p ParametersLiteral[T]) Compile() Parameters[T] {
 	if p.LookUpTableSize == 0 {
 		p.LookUpTableSize = p.PolyDegree
 	}
+	if p.BasePolyDegree == 0 {
+		p.BasePolyDegree = p.PolyDegree
+	}
 	if p.BlockSize == 0 {
 		p.BlockSize = 1
 	}
@@ -397,11 +444,21 @@ func (p ParametersLiteral[T]) Compile() Parameters[T] {
 		panic("LookUpTableSize not multiple of PolyDegree")
 	case !num.IsPowerOfTwo(p.PolyDegree):
 		panic("PolyDegree not power of two")
+	case p.BasePolyDegree <= 0:
+		panic("BasePolyDegree smaller than zero")
+	case !num.IsPowerOfTwo(p.BasePolyDegree):
+		panic("BasePolyDegree not power of two")
+	case p.BasePolyDegree > p.PolyDegree:
+		panic("BasePolyDegree larger than PolyDegree")
+	case p.LWEDimension > p.GLWERank*p.BasePolyDegree:
+		panic("LWEDimension larger than base GLWEDimension")
 	case !(p.BootstrapOrder == OrderKeySwitchBlindRotate || p.BootstrapOrder == OrderBlindRotateKeySwitch):
 		panic("BootstrapOrder not valid")
+	case !(p.KeySwitchMethod == KeySwitchMethodLWE || p.KeySwitchMethod == KeySwitchMethodGLWE):
+		panic("KeySwitchMethod not valid")
 	}
 
-	return Parameters[T]{
+	params := Parameters[T]{
 		lweDimension:     p.LWEDimension,
 		glweDimension:    p.GLWERank * p.PolyDegree,
 		glweRank:         p.GLWERank,
@@ -409,6 +466,9 @@ func (p ParametersLiteral[T]) Compile() Parameters[T] {
 		logPolyDegree:    num.Log2(p.PolyDegree),
 		lookUpTableSize:  p.LookUpTableSize,
 		polyExtendFactor: p.LookUpTableSize / p.PolyDegree,
+		basePolyDegree:   p.BasePolyDegree,
+		baseExtendFactor: p.LookUpTableSize / p.BasePolyDegree,
+		hierarchyDepth:   num.Log2(p.PolyDegree / p.BasePolyDegree),
 
 		lweStdDev:  p.LWEStdDev,
 		glweStdDev: p.GLWEStdDev,
@@ -425,8 +485,12 @@ func (p ParametersLiteral[T]) Compile() Parameters[T] {
 		blindRotateParameters: p.BlindRotateParameters.Compile(),
 		keySwitchParameters:   p.KeySwitchParameters.Compile(),
 
-		bootstrapOrder: p.BootstrapOrder,
+		bootstrapOrder:  p.BootstrapOrder,
+		keySwitchMethod: p.KeySwitchMethod,
 	}
+	params.bivariateMessageModulus = params.estimateBivariateMessageModulus()
+
+	return params
 }
 
 // Parameters are read-only, compiled parameters based on ParametersLiteral.
@@ -446,6 +510,12 @@ type Parameters[T TorusInt] struct {
 	lookUpTableSize int
 	// PolyExtendFactor equals LookUpTableSize / PolyDegree.
 	polyExtendFactor int
+	// BasePolyDegree is the degree of the base ring used in full-domain functional bootstrapping.
+	basePolyDegree int
+	// BaseExtendFactor equals LookUpTableSize / BasePolyDegree.
+	baseExtendFactor int
+	// HierarchyDepth equals log(PolyDegree / BasePolyDegree).
+	hierarchyDepth int
 
 	// LWEStdDev is the normalized standard deviation used for gaussian error sampling in LWE encryption.
 	lweStdDev float64
@@ -459,6 +529,9 @@ type Parameters[T TorusInt] struct {
 
 	// MessageModulus is the modulus of the encoded message.
 	messageModulus T
+	// BivariateMessageModulus is the default message modulus of the second input
+	// of bivariate full-domain bootstrapping.
+	bivariateMessageModulus T
 	// Scale is the scaling factor used for message encoding.
 	// The lower log(Scale) bits are reserved for errors.
 	scale T
@@ -475,6 +548,8 @@ type Parameters[T TorusInt] struct {
 
 	// bootstrapOrder is the order of Programmable Bootstrapping.
 	bootstrapOrder BootstrapOrder
+	// keySwitchMethod is the method of keyswitching for bootstrapping.
+	keySwitchMethod KeySwitchMethod
 }
 
 // DefaultLWEDimension returns the default dimension for LWE entities.
@@ -523,6 +598,29 @@ func (p Parameters[T]) PolyExtendFactor() int {
 	return p.polyExtendFactor
 }
 
+// BasePolyDegree is the degree of the base ring used in full-domain functional bootstrapping.
+func (p Parameters[T]) BasePolyDegree() int {
+	return p.basePolyDegree
+}
+
+// BaseExtendFactor returns LookUpTableSize / BasePolyDegree.
+// This is the number of base-sized blocks the lookup table is decomposed into.
+func (p Parameters[T]) BaseExtendFactor() int {
+	return p.baseExtendFactor
+}
+
+// BaseMessageModulus returns MessageModulus / BaseExtendFactor,
+// which is the message modulus of the base LUT after the recursive decomposition.
+func (p Parameters[T]) BaseMessageModulus() T {
+	return p.messageModulus / T(p.baseExtendFactor)
+}
+
+// HierarchyDepth returns log(PolyDegree / BasePolyDegree),
+// which is the number of reduced rings used in hierarchical full-domain functional bootstrapping.
+func (p Parameters[T]) HierarchyDepth() int {
+	return p.hierarchyDepth
+}
+
 // DefaultLWEStdDev returns the default standard deviation for LWE entities.
 // Returns LWEStdDev if BootstrapOrder is OrderBlindRotateKeySwitch,
 // and GLWEStdDev otherwise.
@@ -611,6 +709,11 @@ func (p Parameters[T]) BootstrapOrder() BootstrapOrder {
 	return p.bootstrapOrder
 }
 
+// KeySwitchMethod is the method of keyswitching for bootstrapping.
+func (p Parameters[T]) KeySwitchMethod() KeySwitchMethod {
+	return p.keySwitchMethod
+}
+
 // IsPublicKeyEncryptable returns true if public key encryption is supported.
 //
 // Currently, public key encryption is supported only with BootstrapOrder OrderKeySwitchBlindRotate.
@@ -618,6 +721,25 @@ func (p Parameters[T]) IsPublicKeyEncryptable() bool {
 	return p.bootstrapOrder == OrderKeySwitchBlindRotate
 }
 
+// AtDepth returns the parameters for the given depth of the evaluator hierarchy,
+// where PolyDegree and LookUpTableSize are halved depth times.
+// AtDepth(0) equals p, and AtDepth(HierarchyDepth) works over the base ring.
+//
+// Panics when depth is smaller than zero or larger than HierarchyDepth.
+func (p Parameters[T]) AtDepth(depth int) Parameters[T] {
+	switch {
+	case depth < 0:
+		panic("depth smaller than zero")
+	case depth > p.hierarchyDepth:
+		panic("depth larger than HierarchyDepth")
+	}
+
+	return p.Literal().
+		WithPolyDegree(p.polyDegree >> depth).
+		WithLookUpTableSize(p.lookUpTableSize >> depth).
+		Compile()
+}
+
 // Literal returns a ParametersLiteral from this Parameters.
 func (p Parameters[T]) Literal() ParametersLiteral[T] {
 	return ParametersLiteral[T]{
@@ -625,6 +747,7 @@ func (p Parameters[T]) Literal() ParametersLiteral[T] {
 		GLWERank:        p.glweRank,
 		PolyDegree:      p.polyDegree,
 		LookUpTableSize: p.lookUpTableSize,
+		BasePolyDegree:  p.basePolyDegree,
 
 		LWEStdDev:  p.lweStdDev,
 		GLWEStdDev: p.glweStdDev,
@@ -636,182 +759,173 @@ func (p Parameters[T]) Literal() ParametersLiteral[T] {
 		BlindRotateParameters: p.blindRotateParameters.Literal(),
 		KeySwitchParameters:   p.keySwitchParameters.Literal(),
 
-		BootstrapOrder: p.bootstrapOrder,
+		BootstrapOrder:  p.bootstrapOrder,
+		KeySwitchMethod: p.keySwitchMethod,
 	}
 }
 
 // EstimateModSwitchStdDev returns an estimated standard deviation of error from modulus switching.
 func (p Parameters[T]) EstimateModSwitchStdDev() float64 {
-	L := float64(p.lookUpTableSize)
-	q := p.floatQ
-
-	h := float64(p.blockCount) * (float64(p.blockSize)) / (float64(p.blockSize + 1))
-
-	modSwitchVar := ((h + 1) * q * q) / (48 * L * L)
-
-	return math.Sqrt(modSwitchVar)
+	return math.Sqrt(p.modSwitchVariance(2 * p.lookUpTableSize))
 }
 
-// EstimateModSwitchStdDev returns an estimated standard deviation of error from modulus switching.
+// EstimateModSwitchNewStdDev returns an estimated standard deviation of error from modulus switching with our New algorithm.
 func (p Parameters[T]) EstimateModSwitchNewStdDev() float64 {
-	L := float64(p.lookUpTableSize)
-	q := p.floatQ
-
-	h := float64(p.blockCount) * (float64(p.blockSize)) / (float64(p.blockSize + 1))
-
-	modSwitchVar := ((h + 1) * q * q) / (12 * L * L)
-
-	return math.Sqrt(modSwitchVar)
+	return math.Sqrt(p.modSwitchVariance(p.lookUpTableSize))
 }
 
 // EstimateBlindRotateStdDev returns an estimated standard deviation of error from Blind Rotation.
 func (p Parameters[T]) EstimateBlindRotateStdDev() float64 {
-	n := float64(p.lweDimension)
-	k := float64(p.glweRank)
-	N := float64(p.polyDegree)
-	beta := p.GLWEStdDevQ()
-	q := p.floatQ
-
-	h := float64(p.blockCount) * (float64(p.blockSize)) / (float64(p.blockSize + 1))
-
-	Bbr := float64(p.blindRotateParameters.Base())
-	Lbr := float64(p.blindRotateParameters.Level())
-
-	blindRotateVar1 := h * (h + (k*N-n)/2 + 1) * (q * q) / (6 * math.Pow(Bbr, 2*Lbr))
-	blindRotateVar2 := n * (Lbr * (k + 1) * N * beta * beta * Bbr * Bbr) / 6
-	//blindRotateFFTVar := n * math.Exp2(-106.6) * (k + 1) * (h + (k*N-n)/2 + 1) * N * (q * q) * Lbr * (Bbr * Bbr)
-	blindRotateVar := blindRotateVar1 + blindRotateVar2 //+ blindRotateFFTVar
-
-	return math.Sqrt(blindRotateVar)
+	return math.Sqrt(p.blindRotateVariance(p.polyDegree))
 }
 
 // EstimateBlindRotateStdDevNew returns an estimated standard deviation of error from Blind Rotation with our New algorithm. (without EBS)
 func (p Parameters[T]) EstimateBlindRotateStdDevNew() float64 {
-	depth := bits.TrailingZeros(uint(p.polyDegree / 2048))
-	blindRotateVar := 0.0
-	for i := 0; i < depth; i++ {
-		n := float64(p.lweDimension)
-		k := float64(p.glweRank)
-		N := float64(p.polyDegree) / math.Pow(2, float64(i+1))
-		beta := p.GLWEStdDevQ()
-		q := p.floatQ
-
-		h := float64(p.blockCount) * (float64(p.blockSize)) / (float64(p.blockSize + 1))
-
-		Bbr := float64(p.blindRotateParameters.Base())
-		Lbr := float64(p.blindRotateParameters.Level())
-
-		blindRotateVar1 := h * (h + (k*N-n)/2 + 1) * (q * q) / (6 * math.Pow(Bbr, 2*Lbr))
-		blindRotateVar2 := n * (Lbr * (k + 1) * N * beta * beta * Bbr * Bbr) / 6
-		blindRotateVar += blindRotateVar1 + blindRotateVar2
-		if i == depth-1 {
-			blindRotateVar += blindRotateVar1 + blindRotateVar2
-		}
-	}
-	return math.Sqrt(blindRotateVar)
+	return math.Sqrt(p.NoiseBudget(PipelineHierarchical).BlindRotateVarianceSum())
 }
 
 // EstimateKeySwitchForBootstrapStdDev returns an estimated standard deviation of error from Key Switching for bootstrapping.
 func (p Parameters[T]) EstimateKeySwitchForBootstrapStdDev() float64 {
-	n := float64(p.lweDimension)
-	k := float64(p.glweRank)
-	N := float64(p.polyDegree)
-	alpha := p.LWEStdDevQ()
-	q := p.floatQ
-
-	Bks := float64(p.keySwitchParameters.Base())
-	Lks := float64(p.keySwitchParameters.Level())
-
-	keySwitchVar1 := ((k*N - n) / 2) * (q * q) / (12 * math.Pow(Bks, 2*Lks))
-	keySwitchVar2 := (k*N - n) * (alpha * alpha * Lks * Bks * Bks) / 12
-	keySwitchVar := keySwitchVar1 + keySwitchVar2
-
-	return math.Sqrt(keySwitchVar)
+	return math.Sqrt(p.keySwitchVariance(p.polyDegree))
 }
 
 // EstimateKeySwitchForBootstrapStdDevNew returns an estimated standard deviation of error from Key Swithcing with our New algorithm. (without EBS)
 func (p Parameters[T]) EstimateKeySwitchForBootstrapStdDevNew() float64 {
-	depth := bits.TrailingZeros(uint(p.polyDegree / 2048))
-	keySwitchVar := 0.0
-
-	for i := 0; i < depth; i++ {
-		n := float64(p.lweDimension)
-		k := float64(p.glweRank)
-		N := float64(p.polyDegree) / math.Pow(2, float64(i+1))
-		alpha := p.LWEStdDevQ()
-		q := p.floatQ
-		Bks := float64(p.keySwitchParameters.Base())
-		Lks := float64(p.keySwitchParameters.Level())
-		keySwitchVar1 := ((k*N - n) / 2) * (q * q) / (12 * math.Pow(Bks, 2*Lks))
-		keySwitchVar2 := (k*N - n) * (alpha * alpha * Lks * Bks * Bks) / 12
-		keySwitchVar += keySwitchVar1 + keySwitchVar2
-		if i == depth-1 {
-			keySwitchVar += keySwitchVar1 + keySwitchVar2
-		}
-	}
-	return math.Sqrt(keySwitchVar)
+	return math.Sqrt(p.NoiseBudget(PipelineHierarchical).KeySwitchVariance)
 }
 
 // EstimateMaxErrorStdDev returns an estimated standard deviation of maximum possible error.
 func (p Parameters[T]) EstimateMaxErrorStdDev() float64 {
-	modSwitchStdDev := p.EstimateModSwitchStdDev()
-	blindRotateStdDev := p.EstimateBlindRotateStdDev()
-	keySwitchStdDev := p.EstimateKeySwitchForBootstrapStdDev()
-	// fmt.Printf("ModSwitch Variance (log scale): %f\n", math.Log2(modSwitchStdDev*modSwitchStdDev))
-	// fmt.Printf("BlindRotate Variance (log scale): %f\n", math.Log2(blindRotateStdDev*blindRotateStdDev))
-	// fmt.Printf("KeySwitch Variance (log scale): %f\n", math.Log2(keySwitchStdDev*keySwitchStdDev))
-	return math.Sqrt(modSwitchStdDev*modSwitchStdDev + blindRotateStdDev*blindRotateStdDev + keySwitchStdDev*keySwitchStdDev)
+	return math.Sqrt(p.NoiseBudget(PipelineClassic).MaxErrorVariance)
 }
 
+// EstimateMaxErrorStdDevNewEBS returns an estimated standard deviation of maximum possible error of [PipelineEBS].
 func (p Parameters[T]) EstimateMaxErrorStdDevNewEBS() float64 {
-	modSwitchStdDev := p.EstimateModSwitchNewStdDev()
-	blindRotateStdDev := p.EstimateBlindRotateStdDev()
-	keySwitchStdDev := p.EstimateKeySwitchForBootstrapStdDev()
-	logExtendFactor := math.Log2(float64(p.polyExtendFactor))
-	// fmt.Printf("ModSwitch Variance (log scale): %f\n", math.Log2(modSwitchStdDev*modSwitchStdDev))
-	// fmt.Printf("BlindRotate Variance (log scale): %f\n", math.Log2((logExtendFactor+1)*blindRotateStdDev*blindRotateStdDev))
-	// fmt.Printf("KeySwitch Variance (log scale): %f\n", math.Log2(keySwitchStdDev*keySwitchStdDev))
-	return math.Sqrt(modSwitchStdDev*modSwitchStdDev + (logExtendFactor+1)*blindRotateStdDev*blindRotateStdDev + keySwitchStdDev*keySwitchStdDev)
+	return math.Sqrt(p.NoiseBudget(PipelineEBS).MaxErrorVariance)
 }
 
+// EstimateMaxErrorStdDevNew returns an estimated standard deviation of maximum possible error of [PipelineHierarchical].
 func (p Parameters[T]) EstimateMaxErrorStdDevNew() float64 {
-	modSwitchStdDev := p.EstimateModSwitchNewStdDev()
-	blindRotateStdDev := p.EstimateBlindRotateStdDevNew()
-	keySwitchStdDev := p.EstimateKeySwitchForBootstrapStdDevNew()
-	// fmt.Printf("ModSwitch Variance (log scale): %f\n", math.Log2(modSwitchStdDev*modSwitchStdDev))
-	// fmt.Printf("BlindRotate Variance (log scale): %f\n", math.Log2(blindRotateStdDev*blindRotateStdDev))
-	// fmt.Printf("KeySwitch Variance (log scale): %f\n", math.Log2(keySwitchStdDev*keySwitchStdDev))
-	return math.Sqrt(modSwitchStdDev*modSwitchStdDev + blindRotateStdDev*blindRotateStdDev + keySwitchStdDev*keySwitchStdDev)
+	return math.Sqrt(p.NoiseBudget(PipelineHierarchical).MaxErrorVariance)
 }
 
 // EstimateFailureProbability returns the failure probability of bootstrapping.
 func (p Parameters[T]) EstimateFailureProbability() float64 {
-	// fmt.Printf("Total Variance (log scale): %f\n", math.Log2(p.EstimateMaxErrorStdDev()*p.EstimateMaxErrorStdDev()))
 	bound := p.floatQ / (4 * float64(p.messageModulus))
-	return math.Erfc(bound / (math.Sqrt2 * p.EstimateMaxErrorStdDev()))
+	return failureProbability(bound, p.NoiseBudget(PipelineClassic).MaxErrorVariance)
 }
 
+// EstimateFailureProbabilityNewFDFB_EBS returns the failure probability of [PipelineEBS].
 func (p Parameters[T]) EstimateFailureProbabilityNewFDFB_EBS() float64 {
-	// fmt.Printf("Total Variance (log scale): %f\n", math.Log2(p.EstimateMaxErrorStdDevNewEBS()*p.EstimateMaxErrorStdDevNewEBS()))
 	bound := p.floatQ / (2 * float64(p.messageModulus))
-	return math.Erfc(bound / (math.Sqrt2 * p.EstimateMaxErrorStdDevNewEBS()))
+	return failureProbability(bound, p.NoiseBudget(PipelineEBS).MaxErrorVariance)
 }
 
+// EstimateFailureProbabilityNewFDFB returns the failure probability of [PipelineHierarchical].
 func (p Parameters[T]) EstimateFailureProbabilityNewFDFB() float64 {
-	// fmt.Printf("Total Variance (log scale): %f\n", math.Log2(p.EstimateMaxErrorStdDevNew()*p.EstimateMaxErrorStdDevNew()))
 	bound := p.floatQ / (2 * float64(p.messageModulus))
-	return math.Erfc(bound / (math.Sqrt2 * p.EstimateMaxErrorStdDevNew()))
+	return failureProbability(bound, p.NoiseBudget(PipelineHierarchical).MaxErrorVariance)
 }
 
+// EstimateFailureProbabilityNewFDFBMultiValue returns the failure probability of
+// full-domain bootstrapping with valueCount functions packed in one decomposed LUT.
+// Each message cell is split into slots, so the error bound is divided by the number of slots.
+func (p Parameters[T]) EstimateFailureProbabilityNewFDFBMultiValue(valueCount int) float64 {
+	bound := p.floatQ / (2 * float64(p.messageModulus) * float64(multiValueSlotCount(valueCount)))
+	return failureProbability(bound, p.NoiseBudget(PipelineHierarchical).MaxErrorVariance)
+}
+
+// EstimateFailureProbabilityNewFDFBBivariate returns the failure probability of
+// bivariate full-domain bootstrapping, where the second input is in [0, messageModulus1).
+// The first input is multiplied by messageModulus1 before packing,
+// so the variance of the input error grows by a factor of messageModulus1^2 + 1.
+func (p Parameters[T]) EstimateFailureProbabilityNewFDFBBivariate(messageModulus1 int) float64 {
+	budget := p.NoiseBudget(PipelineHierarchical)
+
+	inputVar := float64(messageModulus1*messageModulus1+1) * (budget.BlindRotateVarianceSum() + budget.KeySwitchVariance)
+	variance := budget.ModSwitchVariance + inputVar
+
+	bound := p.floatQ / (2 * float64(p.messageModulus))
+	return failureProbability(bound, variance)
+}
+
+// bivariateLogFailureProbability is the log of the largest failure probability
+// allowed for the default split of bivariate full-domain bootstrapping.
+const bivariateLogFailureProbability = -60
+
+// BivariateMessageModulus returns the default message modulus of the second input
+// of bivariate full-domain bootstrapping,
+// so that the first input is in [0, MessageModulus / BivariateMessageModulus).
+//
+// This is the largest power of two up to 2^floor(log(MessageModulus) / 2)
+// such that [Parameters.EstimateFailureProbabilityNewFDFBBivariate] is at most 2^-60.
+// If there is no such split, it returns zero.
+func (p Parameters[T]) BivariateMessageModulus() T {
+	return p.bivariateMessageModulus
+}
+
+// estimateBivariateMessageModulus computes BivariateMessageModulus.
+// It is called once in [ParametersLiteral.Compile].
+func (p Parameters[T]) estimateBivariateMessageModulus() T {
+	for messageModulus1 := 1 << (num.Log2(p.messageModulus) / 2); messageModulus1 >= 1; messageModulus1 >>= 1 {
+		if math.Log2(p.EstimateFailureProbabilityNewFDFBBivariate(messageModulus1)) <= bivariateLogFailureProbability {
+			return T(messageModulus1)
+		}
+	}
+	return 0
+}
+
+// EstimateFailureProbabilityNewFDFBMul returns the failure probability of [*FullDomainEvaluator.MulLWE],
+// including the next bootstrapping of its output.
+// The inputs are assumed to be outputs of full-domain bootstrapping.
+//
+// MulLWE first bootstraps each input, and then bootstraps sums of up to four bootstrapped outputs,
+// so the variance of the input error of the latter four bootstrappings grows by a factor of up to four.
+// The output is also a sum of four bootstrapped outputs.
+func (p Parameters[T]) EstimateFailureProbabilityNewFDFBMul() float64 {
+	budget := p.NoiseBudget(PipelineHierarchical)
+	outputVar := budget.BlindRotateVarianceSum() + budget.KeySwitchVariance
+
+	bound := p.floatQ / (2 * float64(p.messageModulus))
+	fail := func(outputCount float64) float64 {
+		return failureProbability(bound, budget.ModSwitchVariance+outputCount*outputVar)
+	}
+
+	// Two bootstrappings of the inputs, two cross terms with three outputs,
+	// two quarter squares with four outputs, and the next bootstrapping of the output.
+	return 2*fail(1) + 2*fail(3) + 3*fail(4)
+}
+
+// Fingerprint returns the 64-bit FNV-1a hash of the encoded parameters.
+// This is used to check that serialized objects are loaded against the same parameters.
+func (p Parameters[T]) Fingerprint() uint64 {
+	h := fnv.New64a()
+	p.WriteTo(h)
+	return h.Sum64()
+}
+
+const (
+	// parametersVersionMarker marks a versioned encoding of Parameters.
+	// Unversioned encodings start with LWEDimension, which never has the top bit set.
+	parametersVersionMarker = 1 << 63
+	// parametersVersion is the current version of the Parameters encoding.
+	parametersVersion = 1
+
+	// legacyBasePolyDegree is the BasePolyDegree of parameters in the unversioned encoding,
+	// which were always built over the base ring of degree 2048.
+	legacyBasePolyDegree = 2048
+)
+
 // ByteSize returns the byte size of the parameters.
 func (p Parameters[T]) ByteSize() int {
-	return 8*8 + p.blindRotateParameters.ByteSize() + p.keySwitchParameters.ByteSize() + 1
+	return 10*8 + p.blindRotateParameters.ByteSize() + p.keySwitchParameters.ByteSize() + 2
 }
 
 // WriteTo implements the [io.WriterTo] interface.
 //
 // The encoded form is as follows:
 //
+//	[ 8] Version
 //	[ 8] LWEDimension
 //	[ 8] GLWERank
 //	[ 8] PolyDegree
@@ -823,11 +937,22 @@ func (p Parameters[T]) ByteSize() int {
 //	     BlindRotateParameters
 //	     KeySwitchParameters
 //	[ 1] BootstrapOrder
+//	[ 8] BasePolyDegree
+//	[ 1] KeySwitchMethod
+//
+// Version has the top bit set, so that ReadFrom can tell it apart
+// from the unversioned encoding, which starts at LWEDimension and ends at BootstrapOrder.
 func (p Parameters[T]) WriteTo(w io.Writer) (n int64, err error) {
 	var nWrite int
 	var nWrite64 int64
 	var buf [8]byte
 
+	binary.BigEndian.PutUint64(buf[:], parametersVersionMarker|parametersVersion)
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
 	lweDimension := p.lweDimension
 	binary.BigEndian.PutUint64(buf[:], uint64(lweDimension))
 	if nWrite, err = w.Write(buf[:]); err != nil {
@@ -900,6 +1025,19 @@ func (p Parameters[T]) WriteTo(w io.Writer) (n int64, err error) {
 	}
 	n += int64(nWrite)
 
+	basePolyDegree := p.basePolyDegree
+	binary.BigEndian.PutUint64(buf[:], uint64(basePolyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	keySwitchMethod := p.keySwitchMethod
+	if nWrite, err = w.Write([]byte{byte(keySwitchMethod)}); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
 	if n < int64(p.ByteSize()) {
 		return n, io.ErrShortWrite
 	}
@@ -908,6 +1046,10 @@ func (p Parameters[T]) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom implements the [io.ReaderFrom] interface.
+//
+// It also reads the unversioned encoding, which has no BasePolyDegree and KeySwitchMethod.
+// BasePolyDegree is set to min(2048, PolyDegree), the base ring these parameters were built with,
+// and KeySwitchMethod is set to KeySwitchMethodLWE.
 func (p *Parameters[T]) ReadFrom(r io.Reader) (n int64, err error) {
 	var nRead int
 	var nRead64 int64
@@ -917,6 +1059,19 @@ func (p *Parameters[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		return n + int64(nRead), err
 	}
 	n += int64(nRead)
+	version := binary.BigEndian.Uint64(buf[:])
+
+	versioned := version&parametersVersionMarker != 0
+	if versioned {
+		if version != parametersVersionMarker|parametersVersion {
+			return n, errors.New("Parameters version not supported")
+		}
+
+		if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+			return n + int64(nRead), err
+		}
+		n += int64(nRead)
+	}
 	lweDimension := int(binary.BigEndian.Uint64(buf[:]))
 
 	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
@@ -979,11 +1134,28 @@ func (p *Parameters[T]) ReadFrom(r io.Reader) (n int64, err error) {
 	n += int64(nRead)
 	bootstrapOrder := BootstrapOrder(buf[0])
 
+	basePolyDegree := num.Min(legacyBasePolyDegree, polyDegree)
+	keySwitchMethod := KeySwitchMethodLWE
+	if versioned {
+		if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+			return n + int64(nRead), err
+		}
+		n += int64(nRead)
+		basePolyDegree = int(binary.BigEndian.Uint64(buf[:]))
+
+		if nRead, err = io.ReadFull(r, buf[:1]); err != nil {
+			return n + int64(nRead), err
+		}
+		n += int64(nRead)
+		keySwitchMethod = KeySwitchMethod(buf[0])
+	}
+
 	*p = ParametersLiteral[T]{
 		LWEDimension:    lweDimension,
 		GLWERank:        glweRank,
 		PolyDegree:      polyDegree,
 		LookUpTableSize: lookUpTableSize,
+		BasePolyDegree:  basePolyDegree,
 
 		LWEStdDev:  lweStdDev,
 		GLWEStdDev: glweStdDev,
@@ -995,7 +1167,8 @@ func (p *Parameters[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		BlindRotateParameters: blindRotateParameters.Literal(),
 		KeySwitchParameters:   keySwitchParameters.Literal(),
 
-		BootstrapOrder: bootstrapOrder,
+		BootstrapOrder:  bootstrapOrder,
+		KeySwitchMethod: keySwitchMethod,
 	}.Compile()
 
 	return
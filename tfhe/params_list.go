@@ -2,13 +2,14 @@ package tfhe
 
 var (
 	ParamsEBS5 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048,
 		LookUpTableSize: 2048 * 2,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -26,13 +27,14 @@ var (
 		BootstrapOrder: OrderKeySwitchBlindRotate,
 	}
 	ParamsEBS6 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048,
 		LookUpTableSize: 2048 * 4,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -51,13 +53,14 @@ var (
 	}
 
 	ParamsEBS7 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048,
 		LookUpTableSize: 2048 * 8,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -76,13 +79,14 @@ var (
 	}
 
 	ParamsEBS8 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048,
 		LookUpTableSize: 2048 * 16,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -101,13 +105,14 @@ var (
 	}
 
 	Params5 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048 * 2,
 		LookUpTableSize: 2048 * 2,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -125,13 +130,14 @@ var (
 		BootstrapOrder: OrderBlindRotateKeySwitch,
 	}
 	Params6 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048 * 4,
 		LookUpTableSize: 2048 * 4,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -150,13 +156,14 @@ var (
 	}
 
 	Params7 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048 * 8,
 		LookUpTableSize: 2048 * 8,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -175,13 +182,14 @@ var (
 	}
 
 	Params8 = ParametersLiteral[uint64]{
-		LWEDimension:    1160,
+		LWEDimension:    1164,
 		GLWERank:        1,
 		PolyDegree:      2048 * 16,
 		LookUpTableSize: 2048 * 16,
+		BasePolyDegree:  2048,
 
 		LWEStdDev:  0.000000003704451841947947,
-		GLWEStdDev: 0.0000000000000003472576015484159,
+		GLWEStdDev: 0.00000000000000038198336170325749,
 
 		BlockSize: 1,
 
@@ -199,3 +207,161 @@ var (
 		BootstrapOrder: OrderBlindRotateKeySwitch,
 	}
 )
+
+// Parameters over the 32-bit torus for small-precision full-domain bootstrapping.
+var (
+	Params2Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048,
+		BasePolyDegree:  1024,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 2,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderBlindRotateKeySwitch,
+	}
+	Params3Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048,
+		BasePolyDegree:  1024,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 3,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderBlindRotateKeySwitch,
+	}
+	Params4Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048,
+		BasePolyDegree:  1024,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 4,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderBlindRotateKeySwitch,
+	}
+)
+
+// Parameters over the 32-bit torus for small-precision extended full-domain bootstrapping.
+var (
+	ParamsEBS2Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048 * 2,
+		BasePolyDegree:  2048,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 2,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderKeySwitchBlindRotate,
+	}
+	ParamsEBS3Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048 * 2,
+		BasePolyDegree:  2048,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 3,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderKeySwitchBlindRotate,
+	}
+	ParamsEBS4Uint32 = ParametersLiteral[uint32]{
+		LWEDimension:    722,
+		GLWERank:        1,
+		PolyDegree:      2048,
+		LookUpTableSize: 2048 * 2,
+		BasePolyDegree:  2048,
+
+		LWEStdDev:  0.000013071021089943935,
+		GLWEStdDev: 0.00000004990272175010415,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << 4,
+
+		BlindRotateParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 4,
+			Level: 5,
+		},
+		KeySwitchParameters: GadgetParametersLiteral[uint32]{
+			Base:  1 << 2,
+			Level: 7,
+		},
+
+		BootstrapOrder: OrderKeySwitchBlindRotate,
+	}
+)
@@ -0,0 +1,206 @@
+package tfhe
+
+import (
+	"math"
+
+	"github.com/sp301415/tfhe-go/math/num"
+)
+
+// Pipeline is an enum type for the bootstrapping pipelines supported by [Parameters.NoiseBudget].
+type Pipeline int
+
+const (
+	// PipelineClassic is the programmable bootstrapping,
+	// as in [*Evaluator.BootstrapLUTAssign].
+	// Input messages should have a padding bit.
+	PipelineClassic Pipeline = iota
+
+	// PipelineFDFBCompress is the full-domain bootstrapping
+	// which compresses the input by the first bootstrapping,
+	// as in [*Evaluator.FDFBLUTAssign].
+	PipelineFDFBCompress
+
+	// PipelineEBS is the full-domain bootstrapping over extended polynomials,
+	// as in [*Evaluator.BootstrapExtendedFullDomainAssignNew].
+	PipelineEBS
+
+	// PipelineHierarchical is the full-domain bootstrapping over the evaluator hierarchy,
+	// as in [*FullDomainEvaluator.BootstrapLUTAssign].
+	PipelineHierarchical
+)
+
+// String returns the name of the pipeline.
+func (p Pipeline) String() string {
+	switch p {
+	case PipelineClassic:
+		return "Classic"
+	case PipelineFDFBCompress:
+		return "FDFBCompress"
+	case PipelineEBS:
+		return "EBS"
+	case PipelineHierarchical:
+		return "Hierarchical"
+	}
+	return "Unknown"
+}
+
+// NoiseBudget is a breakdown of the estimated error of a bootstrapping pipeline.
+// All variances are over Z_Q.
+type NoiseBudget struct {
+	// Pipeline is the bootstrapping pipeline of this estimate.
+	Pipeline Pipeline
+
+	// ModSwitchVariance is the variance of the error from modulus switching.
+	ModSwitchVariance float64
+	// BlindRotateVariance is the variance of the error from blind rotations
+	// added to the output, for each depth.
+	// This has length HierarchyDepth for PipelineHierarchical, and one otherwise.
+	BlindRotateVariance []float64
+	// KeySwitchVariance is the variance of the error from key switchings.
+	KeySwitchVariance float64
+
+	// OutputVariance is the variance of the error of the output ciphertext.
+	// With OrderKeySwitchBlindRotate, this does not include the key switching error,
+	// since it is added to the input of the next bootstrapping.
+	OutputVariance float64
+	// MaxErrorVariance is the variance of the error at modulus switching,
+	// when the input is the output of the same pipeline.
+	MaxErrorVariance float64
+
+	// LogFailureProbability is the log2 of the failure probability.
+	LogFailureProbability float64
+}
+
+// BlindRotateVarianceSum returns the sum of BlindRotateVariance over all depths.
+func (b NoiseBudget) BlindRotateVarianceSum() float64 {
+	variance := 0.0
+	for _, v := range b.BlindRotateVariance {
+		variance += v
+	}
+	return variance
+}
+
+// NoiseBudget returns the estimated error of given pipeline.
+//
+// Panics when the pipeline is unknown.
+func (p Parameters[T]) NoiseBudget(pipeline Pipeline) NoiseBudget {
+	var budget NoiseBudget
+	bound := p.floatQ / (2 * float64(p.messageModulus))
+
+	switch pipeline {
+	case PipelineClassic, PipelineFDFBCompress:
+		budget = NoiseBudget{
+			ModSwitchVariance:   p.modSwitchVariance(2 * p.lookUpTableSize),
+			BlindRotateVariance: []float64{p.blindRotateVariance(p.polyDegree)},
+			KeySwitchVariance:   p.keySwitchVariance(p.polyDegree),
+		}
+	case PipelineEBS:
+		blindRotateCount := float64(num.Log2(p.baseExtendFactor) + 1)
+		budget = NoiseBudget{
+			ModSwitchVariance:   p.modSwitchVariance(p.lookUpTableSize),
+			BlindRotateVariance: []float64{blindRotateCount * p.blindRotateVariance(p.polyDegree)},
+			KeySwitchVariance:   p.keySwitchVariance(p.polyDegree),
+		}
+	case PipelineHierarchical:
+		// Evaluators at each depth work over polynomials of degree PolyDegree / 2^(depth + 1),
+		// and the last evaluator also evaluates the BaseLUT.
+		blindRotateVariance := make([]float64, p.hierarchyDepth)
+		keySwitchVariance := 0.0
+		for i := 0; i < p.hierarchyDepth; i++ {
+			polyDegree := p.polyDegree >> (i + 1)
+			bootstrapCount := 1.0
+			if i == p.hierarchyDepth-1 {
+				bootstrapCount = 2.0
+			}
+			blindRotateVariance[i] = bootstrapCount * p.blindRotateVariance(polyDegree)
+			keySwitchVariance += bootstrapCount * p.keySwitchVariance(polyDegree)
+		}
+		budget = NoiseBudget{
+			ModSwitchVariance:   p.modSwitchVariance(p.lookUpTableSize),
+			BlindRotateVariance: blindRotateVariance,
+			KeySwitchVariance:   keySwitchVariance,
+		}
+	default:
+		panic("Unknown Pipeline")
+	}
+
+	budget.Pipeline = pipeline
+	budget.OutputVariance = budget.BlindRotateVarianceSum()
+	if p.bootstrapOrder == OrderBlindRotateKeySwitch {
+		budget.OutputVariance += budget.KeySwitchVariance
+	}
+	budget.MaxErrorVariance = budget.ModSwitchVariance + (budget.BlindRotateVarianceSum() + budget.KeySwitchVariance)
+
+	switch pipeline {
+	case PipelineClassic:
+		budget.LogFailureProbability = math.Log2(failureProbability(bound/2, budget.MaxErrorVariance))
+	case PipelineFDFBCompress:
+		// The first bootstrapping reads the full domain,
+		// and the second bootstrapping reads the compressed input with a padding bit.
+		budget.LogFailureProbability = math.Log2(failureProbability(bound, budget.MaxErrorVariance) + failureProbability(bound/2, budget.MaxErrorVariance))
+	default:
+		budget.LogFailureProbability = math.Log2(failureProbability(bound, budget.MaxErrorVariance))
+	}
+
+	return budget
+}
+
+// failureProbability returns the probability that a Gaussian error with given variance
+// has absolute value larger than bound.
+func failureProbability(bound, variance float64) float64 {
+	return math.Erfc(bound / (math.Sqrt2 * math.Sqrt(variance)))
+}
+
+// hammingWeight returns the expected hamming weight of the block binary LWE key.
+func (p Parameters[T]) hammingWeight() float64 {
+	return float64(p.blockCount) * (float64(p.blockSize)) / (float64(p.blockSize + 1))
+}
+
+// modSwitchVariance returns the variance of the error from modulus switching from Q to modulus.
+func (p Parameters[T]) modSwitchVariance(modulus int) float64 {
+	L := float64(modulus)
+	q := p.floatQ
+	h := p.hammingWeight()
+
+	return ((h + 1) * q * q) / (12 * L * L)
+}
+
+// blindRotateVariance returns the variance of the error from a blind rotation
+// over polynomials of given degree.
+func (p Parameters[T]) blindRotateVariance(polyDegree int) float64 {
+	n := float64(p.lweDimension)
+	k := float64(p.glweRank)
+	N := float64(polyDegree)
+	beta := p.GLWEStdDevQ()
+	q := p.floatQ
+	h := p.hammingWeight()
+
+	Bbr := float64(p.blindRotateParameters.Base())
+	Lbr := float64(p.blindRotateParameters.Level())
+
+	blindRotateVar1 := h * (h + (k*N-n)/2 + 1) * (q * q) / (6 * math.Pow(Bbr, 2*Lbr))
+	blindRotateVar2 := n * (Lbr * (k + 1) * N * beta * beta * Bbr * Bbr) / 6
+	//blindRotateFFTVar := n * math.Exp2(-106.6) * (k + 1) * (h + (k*N-n)/2 + 1) * N * (q * q) * Lbr * (Bbr * Bbr)
+	return blindRotateVar1 + blindRotateVar2 //+ blindRotateFFTVar
+}
+
+// keySwitchVariance returns the variance of the error from a key switching for bootstrapping
+// from the LWE key of length GLWERank * polyDegree.
+//
+// With KeySwitchMethodGLWE, only the nonzero coefficients of the packed mask contribute
+// to the constant term, so the error is the same as KeySwitchMethodLWE.
+// As in blindRotateVariance, the error from the fourier transform is ignored.
+func (p Parameters[T]) keySwitchVariance(polyDegree int) float64 {
+	n := float64(p.lweDimension)
+	k := float64(p.glweRank)
+	N := float64(polyDegree)
+	alpha := p.LWEStdDevQ()
+	q := p.floatQ
+
+	Bks := float64(p.keySwitchParameters.Base())
+	Lks := float64(p.keySwitchParameters.Level())
+
+	keySwitchVar1 := ((k*N - n) / 2) * (q * q) / (12 * math.Pow(Bks, 2*Lks))
+	keySwitchVar2 := (k*N - n) * (alpha * alpha * Lks * Bks * Bks) / 12
+	return keySwitchVar1 + keySwitchVar2
+}
@@ -0,0 +1,191 @@
+package tfhe
+
+import (
+	"math"
+
+	"github.com/sp301415/tfhe-go/math/num"
+)
+
+// SecurityEstimate is the estimated security of an LWE instance in bits,
+// for each attack supported by [Parameters.EstimateSecurity].
+type SecurityEstimate struct {
+	// PrimalUSVP is the security against the primal attack,
+	// which solves the unique-SVP instance embedding the secret.
+	PrimalUSVP float64
+	// Dual is the security against the dual attack,
+	// which distinguishes LWE samples by short vectors of the dual lattice.
+	Dual float64
+	// DualHybrid is the security against the dual attack
+	// combined with the exhaustive search over a part of the secret.
+	DualHybrid float64
+}
+
+// Bits returns the minimum security over all attacks.
+func (s SecurityEstimate) Bits() float64 {
+	return num.MinN(s.PrimalUSVP, s.Dual, s.DualHybrid)
+}
+
+// Security is the estimated security of a parameter set.
+type Security struct {
+	// LWE is the security of the LWE problem with LWEDimension and LWEStdDev,
+	// which is used for key switching keys.
+	LWE SecurityEstimate
+	// GLWE is the security of the GLWE problem with GLWEDimension and GLWEStdDev,
+	// which is used for blind rotation keys.
+	GLWE SecurityEstimate
+}
+
+// Bits returns the minimum security of LWE and GLWE problems.
+func (s Security) Bits() float64 {
+	return math.Min(s.LWE.Bits(), s.GLWE.Bits())
+}
+
+// EstimateSecurity returns the estimated security of the parameters.
+//
+// This follows the cost models of the lattice estimator,
+// where BKZ with block size beta costs 2^(0.292 beta + 16.4) operations per SVP call.
+// The number of LWE samples is unbounded, and the GLWE problem is estimated as a plain LWE problem,
+// ignoring the algebraic structure of the ring.
+// Reduced rings of the evaluator hierarchy use the same GLWEStdDev over a smaller dimension,
+// so use [Parameters.AtDepth] to estimate their security.
+//
+// This is only a rough estimate: always validate the parameters
+// with the lattice estimator before using them.
+func (p Parameters[T]) EstimateSecurity() Security {
+	logQ := float64(p.logQ)
+
+	// Each block of the LWE key has at most one nonzero entry,
+	// so each coefficient is one with probability 1 / (BlockSize + 1).
+	lweKeyProb := 1 / float64(p.blockSize+1)
+	lweKeyStdDev := math.Sqrt(lweKeyProb * (1 - lweKeyProb))
+	// Each block of the LWE key takes one of BlockSize + 1 values.
+	lweKeyEntropy := math.Log2(float64(p.blockSize+1)) / float64(p.blockSize)
+
+	return Security{
+		LWE:  estimateLWESecurity(p.lweDimension, logQ, math.Log2(p.LWEStdDevQ()), lweKeyStdDev, lweKeyEntropy),
+		GLWE: estimateLWESecurity(p.glweDimension, logQ, math.Log2(p.GLWEStdDevQ()), 0.5, 1),
+	}
+}
+
+const (
+	// minBKZBlockSize is the smallest block size of BKZ considered,
+	// where the root Hermite factor estimate is valid.
+	minBKZBlockSize = 40
+	// logSieveVectors is the log2 of the number of short vectors
+	// obtained from a single sieving call, divided by the block size.
+	logSieveVectors = 0.2075
+)
+
+// estimateLWESecurity returns the estimated security of the LWE problem
+// of dimension n with modulus 2^logQ, error standard deviation 2^logStdDev and key standard deviation keyStdDev.
+// keyEntropy is the entropy of the key per coefficient in bits.
+func estimateLWESecurity(n int, logQ, logStdDev, keyStdDev, keyEntropy float64) SecurityEstimate {
+	return SecurityEstimate{
+		PrimalUSVP: estimatePrimalUSVP(n, logQ, logStdDev, keyStdDev),
+		Dual:       estimateDualHybrid(n, 0, logQ, logStdDev, keyStdDev, keyEntropy),
+		DualHybrid: estimateDualHybridBest(n, logQ, logStdDev, keyStdDev, keyEntropy),
+	}
+}
+
+// logRootHermiteFactor returns the log2 of the root Hermite factor of BKZ with block size beta.
+func logRootHermiteFactor(beta int) float64 {
+	b := float64(beta)
+	return math.Log2(math.Pow(math.Pi*b, 1/b)*b/(2*math.Pi*math.E)) / (2 * (b - 1))
+}
+
+// logBKZCost returns the log2 of the cost of BKZ with block size beta over a lattice of given dimension.
+// This is the BDGL16 sieving cost, with 8 * dimension SVP calls.
+func logBKZCost(beta, dimension int) float64 {
+	return 0.292*float64(beta) + 16.4 + math.Log2(8*float64(dimension))
+}
+
+// logAdd returns log2(2^x + 2^y).
+func logAdd(x, y float64) float64 {
+	if x < y {
+		x, y = y, x
+	}
+	if math.IsInf(x, 1) {
+		return x
+	}
+	return x + math.Log2(1+math.Exp2(y-x))
+}
+
+// estimatePrimalUSVP returns the estimated security against the primal uSVP attack.
+//
+// The secret is scaled by stdDev / keyStdDev to balance the embedded vector,
+// and BKZ with block size beta succeeds over a lattice of dimension d with volume vol when
+// sqrt(beta) * stdDev <= delta^(2beta - d - 1) * vol^(1/d).
+func estimatePrimalUSVP(n int, logQ, logStdDev, keyStdDev float64) float64 {
+	logScale := logStdDev - math.Log2(keyStdDev)
+	logVolumeSlope := float64(n+1)*logQ - float64(n)*logScale
+
+	for beta := minBKZBlockSize; beta <= 4*n; beta++ {
+		logDelta := logRootHermiteFactor(beta)
+
+		// The right hand side is maximized at d = sqrt(((n + 1) logQ - n logScale) / logDelta).
+		d := int(math.Round(math.Sqrt(logVolumeSlope / logDelta)))
+		d = num.Min(num.Max(d, n+2), 4*n)
+		m := d - n - 1
+
+		lhs := 0.5*math.Log2(float64(beta)) + logStdDev
+		rhs := float64(2*beta-d-1)*logDelta + (float64(m)*logQ+float64(n)*logScale)/float64(d)
+		if lhs <= rhs {
+			return logBKZCost(beta, d)
+		}
+	}
+	return math.Inf(1)
+}
+
+// estimateDualHybridBest returns the estimated security against the dual hybrid attack,
+// minimized over the number of guessed coefficients.
+func estimateDualHybridBest(n int, logQ, logStdDev, keyStdDev, keyEntropy float64) float64 {
+	step := num.Max(n/256, 1)
+
+	security := math.Inf(1)
+	// Guessing zeta coefficients costs at least 2^(zeta * keyEntropy).
+	for zeta := 0; zeta < n && float64(zeta)*keyEntropy < security; zeta += step {
+		security = math.Min(security, estimateDualHybrid(n, zeta, logQ, logStdDev, keyStdDev, keyEntropy))
+	}
+	return security
+}
+
+// estimateDualHybrid returns the estimated security against the dual attack,
+// where zeta coefficients of the secret are exhaustively guessed over 2^(zeta * keyEntropy) candidates.
+// If zeta is zero, this is the plain dual attack.
+//
+// A short dual vector of length l gives a sample with error l * stdDev and advantage
+// eps = exp(-2 pi^2 (l * stdDev / Q)^2), so that zeta / eps^2 samples distinguish the correct guess.
+// Every sieving call gives 2^(0.2075 beta) short vectors,
+// and every guess is evaluated against every sample.
+func estimateDualHybrid(n, zeta int, logQ, logStdDev, keyStdDev, keyEntropy float64) float64 {
+	n -= zeta
+	logScale := math.Log2(keyStdDev) - logStdDev
+	logVolume := float64(n) * (logQ + logScale)
+
+	security := math.Inf(1)
+	for beta := minBKZBlockSize; beta <= 4*n; beta++ {
+		logDelta := logRootHermiteFactor(beta)
+
+		// The length of the short vector is minimized at d = sqrt(n (logQ + logScale) / logDelta).
+		d := int(math.Round(math.Sqrt(logVolume / logDelta)))
+		d = num.Min(num.MaxN(d, beta, n+1), 4*n)
+
+		// Short vectors from a sieving call are longer than the shortest one by a factor of sqrt(4/3).
+		logLength := float64(d)*logDelta + logVolume/float64(d) + 0.5*math.Log2(4.0/3.0)
+		logNoise := logLength + logStdDev - logQ
+		logInvAdvantage := 4 * math.Pi * math.Pi * math.Exp2(2*logNoise) * math.Log2E
+		logSamples := logInvAdvantage + math.Log2(math.Max(float64(zeta)*keyEntropy, 1))
+
+		logCost := logBKZCost(beta, d) + math.Max(0, logSamples-logSieveVectors*float64(beta))
+		if zeta > 0 {
+			logCost = logAdd(logCost, logSamples+float64(zeta)*keyEntropy)
+		}
+		security = math.Min(security, logCost)
+
+		// The cost only grows once BKZ dominates.
+		if logBKZCost(beta, d) > security {
+			break
+		}
+	}
+	return security
+}
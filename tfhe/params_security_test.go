@@ -0,0 +1,61 @@
+package tfhe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// securityTarget is the security level of the parameter list in bits.
+	securityTarget = 128
+)
+
+// testSecurity checks that params meets securityTarget at every depth of the evaluator hierarchy.
+func testSecurity[T tfhe.TorusInt](t *testing.T, name string, params tfhe.Parameters[T]) {
+	for depth := 0; depth <= params.HierarchyDepth(); depth++ {
+		paramsDepth := params.AtDepth(depth)
+		t.Run(fmt.Sprintf("%v/Depth%v", name, depth), func(t *testing.T) {
+			security := paramsDepth.EstimateSecurity()
+			t.Logf("LWE=%+v GLWE=%+v", security.LWE, security.GLWE)
+			assert.GreaterOrEqual(t, security.Bits(), float64(securityTarget))
+		})
+	}
+}
+
+func TestEstimateSecurity(t *testing.T) {
+	for _, params := range paramsList {
+		testSecurity(t, fmt.Sprintf("Params%v", num.Log2(params.MessageModulus)), params.Compile())
+	}
+
+	for _, params := range paramsListEBS {
+		testSecurity(t, fmt.Sprintf("ParamsEBS%v", num.Log2(params.MessageModulus)), params.Compile())
+	}
+
+	for _, params := range paramsListUint32 {
+		testSecurity(t, fmt.Sprintf("Params%vUint32", num.Log2(params.MessageModulus)), params.Compile())
+	}
+
+	for _, params := range paramsListEBSUint32 {
+		testSecurity(t, fmt.Sprintf("ParamsEBS%vUint32", num.Log2(params.MessageModulus)), params.Compile())
+	}
+
+	t.Run("Monotone", func(t *testing.T) {
+		params := tfhe.Params5.Compile()
+		security := params.EstimateSecurity()
+
+		// Smaller errors are easier to attack.
+		weak := params.Literal().WithLWEStdDev(params.LWEStdDev() / 16).Compile().EstimateSecurity()
+		assert.Less(t, weak.LWE.Bits(), security.LWE.Bits())
+		assert.Equal(t, security.GLWE, weak.GLWE)
+
+		// Reduced rings are easier to attack.
+		assert.Less(t, params.AtDepth(params.HierarchyDepth()).EstimateSecurity().GLWE.Bits(), security.GLWE.Bits())
+
+		assert.LessOrEqual(t, security.LWE.DualHybrid, security.LWE.Dual)
+		assert.Equal(t, security.LWE.Bits(), num.MinN(security.LWE.PrimalUSVP, security.LWE.Dual, security.LWE.DualHybrid))
+	})
+}
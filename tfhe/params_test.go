@@ -0,0 +1,203 @@
+package tfhe_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasePolyDegree(t *testing.T) {
+	t.Run("Compile", func(t *testing.T) {
+		assert.Equal(t, tfhe.Params5.PolyDegree, tfhe.Params5.WithBasePolyDegree(0).Compile().BasePolyDegree())
+		assert.Panics(t, func() { tfhe.Params5.WithBasePolyDegree(-1).Compile() })
+		assert.Panics(t, func() { tfhe.Params5.WithBasePolyDegree(1000).Compile() })
+		assert.Panics(t, func() { tfhe.Params5.WithBasePolyDegree(2 * tfhe.Params5.PolyDegree).Compile() })
+		assert.Panics(t, func() { tfhe.Params5.WithBasePolyDegree(512).Compile() })
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		params := tfhe.Params6.WithBasePolyDegree(4096).Compile()
+
+		data, err := params.MarshalBinary()
+		assert.NoError(t, err)
+
+		var paramsOut tfhe.Parameters[uint64]
+		assert.NoError(t, paramsOut.UnmarshalBinary(data))
+		assert.Equal(t, params.Literal(), paramsOut.Literal())
+		assert.Equal(t, params.HierarchyDepth(), paramsOut.HierarchyDepth())
+	})
+
+	t.Run("Marshal/Unversioned", func(t *testing.T) {
+		for _, params := range []tfhe.ParametersLiteral[uint64]{tfhe.Params5, tfhe.Params6} {
+			params := params.Compile()
+
+			data, err := params.MarshalBinary()
+			assert.NoError(t, err)
+
+			// The unversioned encoding starts at LWEDimension and ends at BootstrapOrder.
+			// Trailing bytes belong to the next object in the stream.
+			next := []byte{0xde, 0xad, 0xbe, 0xef}
+			unversioned := append(append([]byte{}, data[8:len(data)-9]...), next...)
+
+			var paramsOut tfhe.Parameters[uint64]
+			buf := bytes.NewBuffer(unversioned)
+			n, err := paramsOut.ReadFrom(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(data)-17), n)
+			assert.Equal(t, next, buf.Bytes())
+
+			// Unversioned parameters were built over the base ring of degree 2048.
+			assert.Equal(t, 2048, paramsOut.BasePolyDegree())
+			assert.Equal(t, params.HierarchyDepth(), paramsOut.HierarchyDepth())
+			assert.Equal(t, params.Fingerprint(), paramsOut.Fingerprint())
+			assert.Equal(t, params, paramsOut)
+		}
+	})
+
+	t.Run("Marshal/Versioned", func(t *testing.T) {
+		params := tfhe.Params6.Compile()
+
+		data, err := params.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, params.ByteSize(), len(data))
+
+		var paramsOut tfhe.Parameters[uint64]
+		assert.Error(t, paramsOut.UnmarshalBinary(data[:len(data)-1]))
+		assert.Error(t, paramsOut.UnmarshalBinary(data[:len(data)-5]))
+
+		data[7]++
+		assert.Error(t, paramsOut.UnmarshalBinary(data))
+	})
+
+	for _, params := range paramsListNew {
+		params := params.Compile()
+
+		t.Run(fmt.Sprintf("CompressLUT/ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			// After modulus switching to 2 * BasePolyDegree, base messages are 2 * BasePolyDegree / BaseMessageModulus apart.
+			// Every input within half of this distance must be compressed to the same message.
+			lut := tfhe.NewLookUpTableCustom[uint64](1, params.BasePolyDegree())
+			tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint64]{}).GenCompressLUTAssign(lut)
+
+			polyDegree := params.BasePolyDegree()
+			baseMessageModulus := int(params.BaseMessageModulus())
+			halfQ := uint64(1) << (params.LogQ() - 1)
+			cellSize := 2 * polyDegree / baseMessageModulus
+			for x := 0; x < baseMessageModulus/2; x++ {
+				want := (halfQ/uint64(baseMessageModulus))*uint64(x) + halfQ/2/uint64(baseMessageModulus)
+				for d := -cellSize/2 + 1; d < cellSize/2; d++ {
+					// Constant coefficient of X^-p * LUT.
+					p := x*cellSize + d
+					got := lut.Value[0].Coeffs[(p+polyDegree)%polyDegree]
+					if p < 0 {
+						got = -got
+					}
+					if got != want {
+						t.Fatalf("x=%v d=%v: got %v, want %v", x, d, got, want)
+					}
+				}
+			}
+		})
+	}
+
+	paramsListBase := []tfhe.ParametersLiteral[uint64]{
+		tfhe.Params5.WithPolyDegree(2048).WithLookUpTableSize(2048).WithBasePolyDegree(1024).WithLWEDimension(1000).WithMessageModulus(1 << 4),
+		tfhe.Params5,
+		tfhe.Params6.WithBasePolyDegree(4096),
+	}
+
+	for _, params := range paramsListBase {
+		params := params.Compile()
+
+		t.Run(fmt.Sprintf("FDFB/BasePolyDegree=%v", params.BasePolyDegree()), func(t *testing.T) {
+			assert.Equal(t, num.Log2(params.PolyDegree()/params.BasePolyDegree()), params.HierarchyDepth())
+			assert.Equal(t, params.LookUpTableSize()/params.BasePolyDegree(), params.BaseExtendFactor())
+
+			enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+			assert.Equal(t, params.HierarchyDepth(), len(enc))
+			assert.Equal(t, params.BasePolyDegree(), enc[len(enc)-1].Parameters.PolyDegree())
+
+			evaluators := make([]*tfhe.Evaluator[uint64], len(enc))
+			for depth := 0; depth < len(enc); depth++ {
+				evaluators[depth] = tfhe.NewEvaluatorHierarchy(params, enc[depth].GenEvaluationKeyParallel(), depth+1)
+			}
+			last := evaluators[len(evaluators)-1]
+
+			// LUT generation does not need any evaluation keys.
+			baseEval := tfhe.NewEvaluator(params, tfhe.EvaluationKey[uint64]{})
+
+			f := func(x int) int { return 3*x + 1 }
+			decomposedLUT := baseEval.NewDecomposedLut()
+			baseEval.GenLookUpTableNegDecomposedAssign(f, params.MessageModulus(), params.Scale(), &decomposedLUT)
+			compressLUT := tfhe.NewLookUpTable(last.Parameters)
+			baseEval.GenCompressLUTAssign(compressLUT)
+			MSConst := baseEval.ModSwitchConstant()
+
+			messageModulus := int(params.MessageModulus())
+			for _, x := range []int{0, 1, messageModulus/2 - 1, messageModulus / 2, messageModulus - 1} {
+				ct := enc[0].EncryptLWE(x)
+				ctOut := enc[0].EncryptLWE(0)
+				for depth := 0; depth < len(evaluators); depth++ {
+					evaluators[depth].AddLWEAssign(ctOut, evaluators[depth].BootstrapLUTWithMSconst(ct, decomposedLUT.NegLUTs[depth], MSConst), ctOut)
+				}
+				ctCompress := last.BootstrapLUTWithMSconst(ct, compressLUT, MSConst*2)
+				last.AddLWEAssign(ctOut, last.BootstrapLUT(ctCompress, decomposedLUT.BaseLUT), ctOut)
+
+				assert.Equal(t, f(x)%messageModulus, enc[0].DecryptLWE(ctOut))
+			}
+		})
+	}
+}
+
+func TestParamsAtDepth(t *testing.T) {
+	for _, params := range paramsListNew {
+		params := params.Compile()
+
+		t.Run(fmt.Sprintf("ParamsUint%v", num.Log2(params.MessageModulus())), func(t *testing.T) {
+			assert.Equal(t, params, params.AtDepth(0))
+			assert.Panics(t, func() { params.AtDepth(-1) })
+			assert.Panics(t, func() { params.AtDepth(params.HierarchyDepth() + 1) })
+
+			for depth := 0; depth <= params.HierarchyDepth(); depth++ {
+				paramsDepth := params.AtDepth(depth)
+
+				assert.Equal(t, params.PolyDegree()>>depth, paramsDepth.PolyDegree())
+				assert.Equal(t, params.LogPolyDegree()-depth, paramsDepth.LogPolyDegree())
+				assert.Equal(t, params.LookUpTableSize()>>depth, paramsDepth.LookUpTableSize())
+				assert.Equal(t, paramsDepth.GLWERank()*paramsDepth.PolyDegree(), paramsDepth.GLWEDimension())
+				assert.Equal(t, paramsDepth.LookUpTableSize()/paramsDepth.PolyDegree(), paramsDepth.PolyExtendFactor())
+				assert.Equal(t, paramsDepth.LookUpTableSize()/paramsDepth.BasePolyDegree(), paramsDepth.BaseExtendFactor())
+				assert.Equal(t, params.HierarchyDepth()-depth, paramsDepth.HierarchyDepth())
+				assert.Equal(t, paramsDepth.LWEDimension()/paramsDepth.BlockSize(), paramsDepth.BlockCount())
+
+				assert.Equal(t, params.BasePolyDegree(), paramsDepth.BasePolyDegree())
+				assert.Equal(t, params.LWEDimension(), paramsDepth.LWEDimension())
+				assert.Equal(t, params.MessageModulus(), paramsDepth.MessageModulus())
+				assert.Equal(t, params.Scale(), paramsDepth.Scale())
+				assert.Equal(t, params.LogQ(), paramsDepth.LogQ())
+				assert.Equal(t, params.BlindRotateParameters(), paramsDepth.BlindRotateParameters())
+				assert.Equal(t, params.KeySwitchParameters(), paramsDepth.KeySwitchParameters())
+				assert.Equal(t, params.BootstrapOrder(), paramsDepth.BootstrapOrder())
+
+				assert.Equal(t, paramsDepth, paramsDepth.Literal().Compile())
+			}
+		})
+	}
+
+	t.Run("Hierarchy", func(t *testing.T) {
+		params := tfhe.Params6.Compile()
+		enc := tfhe.NewEncryptorHierarchyWithSharedLWEKey(params)
+		evaluators := tfhe.NewEvaluatorHierarchyFromKey(tfhe.HierarchicalEvaluationKey[uint64]{
+			Parameters: params,
+			Value:      make([]tfhe.EvaluationKey[uint64], params.HierarchyDepth()),
+		})
+
+		for depth := range enc {
+			assert.Equal(t, params.AtDepth(depth+1), enc[depth].Parameters)
+			assert.Equal(t, params.AtDepth(depth+1), evaluators[depth].Parameters)
+		}
+	})
+}
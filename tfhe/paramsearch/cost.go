@@ -0,0 +1,89 @@
+package paramsearch
+
+import (
+	"math"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+const (
+	// securityIntercept and securitySlope fit the log2 of the standard deviation
+	// as a linear function of the LWE dimension at 128-bit security,
+	// so that the parameters in the parameter list are reproduced.
+	securityIntercept = 2.5525
+	securitySlope     = -0.026255
+
+	// minStdDevQ is the smallest standard deviation over Z_Q.
+	minStdDevQ = 3.2
+)
+
+// EstimateStdDev returns the standard deviation of the error over the torus,
+// so that LWE of given dimension achieves given security level with binary keys.
+//
+// This is a heuristic, where the log2 of the standard deviation is fitted linearly to the dimension
+// from the parameters in the parameter list, and the dimension is scaled linearly by the security level.
+// The output is not smaller than 3.2 over Z_Q, as recommended by the homomorphic encryption standard.
+// The security is not guaranteed: check it with [tfhe.Parameters.EstimateSecurity], as [Search] does.
+func EstimateStdDev[T tfhe.TorusInt](dimension, securityLevel int) float64 {
+	effectiveDimension := float64(dimension) * 128 / float64(securityLevel)
+	stdDev := math.Exp2(securityIntercept + securitySlope*effectiveDimension)
+	return math.Max(stdDev, minStdDevQ/math.Exp2(float64(num.SizeT[T]())))
+}
+
+// blindRotateCost returns the estimated cost of a blind rotation over polynomials of given degree.
+// Each external product consists of (GLWERank + 1) * Level forward FFTs,
+// GLWERank + 1 inverse FFTs, and (GLWERank + 1)^2 * Level pointwise products.
+func blindRotateCost[T tfhe.TorusInt](params tfhe.Parameters[T], polyDegree int) float64 {
+	n := float64(params.LWEDimension())
+	k := float64(params.GLWERank())
+	N := float64(polyDegree)
+	Lbr := float64(params.BlindRotateParameters().Level())
+
+	fftCost := (k + 1) * (Lbr + 1) * N * math.Log2(N)
+	mulCost := (k + 1) * (k + 1) * Lbr * N
+	return n * (fftCost + mulCost)
+}
+
+// keySwitchCost returns the estimated cost of a key switching for bootstrapping
+// from the LWE key of length GLWERank * polyDegree.
+func keySwitchCost[T tfhe.TorusInt](params tfhe.Parameters[T], polyDegree int) float64 {
+	n := float64(params.LWEDimension())
+	k := float64(params.GLWERank())
+	N := float64(polyDegree)
+	Lks := float64(params.KeySwitchParameters().Level())
+
+	return (k*N - n) * Lks * (n + 1)
+}
+
+// EstimateCost returns the estimated cost of the pipeline with given parameters,
+// in the number of floating point operations.
+// This is meant for comparing parameters, not for predicting the running time.
+//
+// Panics when the pipeline is unknown.
+func EstimateCost[T tfhe.TorusInt](params tfhe.Parameters[T], pipeline tfhe.Pipeline) float64 {
+	N := params.PolyDegree()
+	extendFactor := float64(params.PolyExtendFactor())
+
+	switch pipeline {
+	case tfhe.PipelineClassic:
+		return extendFactor*blindRotateCost(params, N) + keySwitchCost(params, N)
+	case tfhe.PipelineFDFBCompress:
+		return 2 * (extendFactor*blindRotateCost(params, N) + keySwitchCost(params, N))
+	case tfhe.PipelineEBS:
+		// NegLUTs are evaluated over extended polynomials of size PolyExtendFactor / 2^(i+1),
+		// which sum up to PolyExtendFactor - 1, followed by the compression and the BaseLUT.
+		return (extendFactor+1)*blindRotateCost(params, N) + keySwitchCost(params, N)
+	case tfhe.PipelineHierarchical:
+		// Each depth bootstraps the NegLUT, and the last depth also bootstraps
+		// the compression and the BaseLUT.
+		cost := 0.0
+		for i := 0; i < params.HierarchyDepth(); i++ {
+			polyDegree := N >> (i + 1)
+			cost += blindRotateCost(params, polyDegree) + keySwitchCost(params, polyDegree)
+		}
+		baseDegree := N >> params.HierarchyDepth()
+		return cost + 2*(blindRotateCost(params, baseDegree)+keySwitchCost(params, baseDegree))
+	}
+	panic("Unknown Pipeline")
+}
@@ -0,0 +1,256 @@
+// Package paramsearch implements the automatic search of TFHE parameters
+// for a target precision, failure probability and security level.
+//
+// Candidates are pruned by the noise and security estimators of [tfhe.Parameters],
+// and ranked by the estimated cost of bootstrapping.
+// Unless you are a cryptographic expert, always validate the found parameters
+// before adding them to the parameter list.
+package paramsearch
+
+import (
+	"sort"
+
+	"github.com/sp301415/tfhe-go/math/num"
+	"github.com/sp301415/tfhe-go/tfhe"
+)
+
+// Target is the requirement for the parameter search.
+type Target struct {
+	// MessageBits is the number of bits of messages.
+	MessageBits int
+	// LogFailureProbability is the log2 of the largest allowed failure probability.
+	LogFailureProbability float64
+	// SecurityLevel is the security level in bits.
+	SecurityLevel int
+	// Pipeline is the bootstrapping pipeline.
+	Pipeline tfhe.Pipeline
+}
+
+// Space is the search space of the parameter search.
+// Every combination of the values is enumerated,
+// except for the gadget bases which are chosen to minimize the error for each level.
+type Space struct {
+	// LWEDimensions are the candidates for LWEDimension.
+	LWEDimensions []int
+	// GLWERanks are the candidates for GLWERank.
+	GLWERanks []int
+	// BasePolyDegrees are the candidates for BasePolyDegree.
+	BasePolyDegrees []int
+	// ExtendFactors are the candidates for LookUpTableSize / BasePolyDegree.
+	// For PipelineHierarchical, this is PolyDegree / BasePolyDegree and should be at least two.
+	// Otherwise, this is LookUpTableSize / PolyDegree.
+	ExtendFactors []int
+	// BlindRotateLevels are the candidates for the level of BlindRotateParameters.
+	BlindRotateLevels []int
+	// KeySwitchLevels are the candidates for the level of KeySwitchParameters.
+	KeySwitchLevels []int
+}
+
+// DefaultSpace returns the default search space,
+// which covers the parameters in the parameter list.
+func DefaultSpace() Space {
+	lweDimensions := make([]int, 0)
+	for n := 512; n <= 1536; n += 8 {
+		lweDimensions = append(lweDimensions, n)
+	}
+
+	return Space{
+		LWEDimensions:     lweDimensions,
+		GLWERanks:         []int{1},
+		BasePolyDegrees:   []int{512, 1024, 2048, 4096},
+		ExtendFactors:     []int{1, 2, 4, 8, 16, 32},
+		BlindRotateLevels: []int{1, 2, 3, 4, 5, 6},
+		KeySwitchLevels:   []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+	}
+}
+
+// Candidate is a parameter set found by the parameter search.
+type Candidate[T tfhe.TorusInt] struct {
+	// Parameters is the parameters of this Candidate.
+	Parameters tfhe.ParametersLiteral[T]
+	// NoiseBudget is the estimated error of Parameters for the target pipeline.
+	NoiseBudget tfhe.NoiseBudget
+	// Cost is the estimated cost of bootstrapping,
+	// in the number of floating point operations.
+	Cost float64
+}
+
+// Search returns every parameter set in space satisfying target,
+// sorted by the estimated cost of bootstrapping.
+// The result is empty if no parameter set satisfies target.
+//
+// Standard deviations are chosen by [EstimateStdDev],
+// and parameter sets are dropped unless [tfhe.Parameters.EstimateSecurity]
+// is at least SecurityLevel at every depth of the evaluator hierarchy.
+//
+// Panics when MessageBits is smaller than one or SecurityLevel is smaller than zero.
+func Search[T tfhe.TorusInt](target Target, space Space) []Candidate[T] {
+	switch {
+	case target.MessageBits < 1:
+		panic("MessageBits smaller than one")
+	case target.MessageBits >= num.SizeT[T]():
+		panic("MessageBits larger than log(Q)")
+	case target.SecurityLevel <= 0:
+		panic("SecurityLevel smaller than zero")
+	}
+
+	candidates := make([]Candidate[T], 0)
+	for _, k := range space.GLWERanks {
+		for _, basePolyDegree := range space.BasePolyDegrees {
+			glweStdDev := EstimateStdDev[T](k*basePolyDegree, target.SecurityLevel)
+			for _, extendFactor := range space.ExtendFactors {
+				for _, n := range space.LWEDimensions {
+					if n > k*basePolyDegree {
+						continue
+					}
+
+					params, ok := newParametersLiteral[T](target, n, k, basePolyDegree, extendFactor)
+					if !ok {
+						continue
+					}
+					params.LWEStdDev = EstimateStdDev[T](n, target.SecurityLevel)
+					params.GLWEStdDev = glweStdDev
+					if !isSecure(target, params) {
+						continue
+					}
+
+					candidates = append(candidates, searchGadget(target, space, params)...)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Cost != candidates[j].Cost {
+			return candidates[i].Cost < candidates[j].Cost
+		}
+		return candidates[i].NoiseBudget.LogFailureProbability < candidates[j].NoiseBudget.LogFailureProbability
+	})
+
+	return candidates
+}
+
+// newParametersLiteral returns the parameters of the target pipeline without noise and gadget parameters.
+// It returns false if the combination is not valid for the pipeline.
+func newParametersLiteral[T tfhe.TorusInt](target Target, n, k, basePolyDegree, extendFactor int) (tfhe.ParametersLiteral[T], bool) {
+	if extendFactor < 1 || !num.IsPowerOfTwo(extendFactor) {
+		return tfhe.ParametersLiteral[T]{}, false
+	}
+
+	params := tfhe.ParametersLiteral[T]{
+		LWEDimension: n,
+		GLWERank:     k,
+
+		BlockSize: 1,
+
+		MessageModulus: 1 << target.MessageBits,
+	}
+
+	// Full-domain pipelines decompose the LUT down to BaseMessageModulus,
+	// which should have at least two messages.
+	switch target.Pipeline {
+	case tfhe.PipelineHierarchical, tfhe.PipelineEBS:
+		if 1<<target.MessageBits < 2*extendFactor {
+			return tfhe.ParametersLiteral[T]{}, false
+		}
+	}
+
+	switch target.Pipeline {
+	case tfhe.PipelineHierarchical:
+		if extendFactor < 2 {
+			return tfhe.ParametersLiteral[T]{}, false
+		}
+		params.PolyDegree = basePolyDegree * extendFactor
+		params.LookUpTableSize = basePolyDegree * extendFactor
+		params.BasePolyDegree = basePolyDegree
+		params.BootstrapOrder = tfhe.OrderBlindRotateKeySwitch
+	default:
+		params.PolyDegree = basePolyDegree
+		params.LookUpTableSize = basePolyDegree * extendFactor
+		params.BasePolyDegree = basePolyDegree
+		params.BootstrapOrder = tfhe.OrderKeySwitchBlindRotate
+	}
+
+	return params, true
+}
+
+// isSecure returns true if the LWE and GLWE problems of params
+// have at least SecurityLevel bits of security at every depth of the evaluator hierarchy.
+// The security does not depend on the gadget parameters, which may be unset.
+func isSecure[T tfhe.TorusInt](target Target, params tfhe.ParametersLiteral[T]) bool {
+	params.BlindRotateParameters = tfhe.GadgetParametersLiteral[T]{Base: 2, Level: 1}
+	params.KeySwitchParameters = tfhe.GadgetParametersLiteral[T]{Base: 2, Level: 1}
+	paramsCompiled := params.Compile()
+
+	for depth := 0; depth <= paramsCompiled.HierarchyDepth(); depth++ {
+		if paramsCompiled.AtDepth(depth).EstimateSecurity().Bits() < float64(target.SecurityLevel) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchGadget chooses the gadget parameters of params for each level in space,
+// and returns the candidates satisfying target.
+//
+// For a fixed level, the cost does not depend on the base,
+// so the base is chosen to minimize the error.
+// The errors of blind rotation and key switching are independent,
+// so the bases are chosen separately.
+func searchGadget[T tfhe.TorusInt](target Target, space Space, params tfhe.ParametersLiteral[T]) []Candidate[T] {
+	logQ := num.SizeT[T]()
+
+	params.BlindRotateParameters = tfhe.GadgetParametersLiteral[T]{Base: 2, Level: 1}
+	params.KeySwitchParameters = tfhe.GadgetParametersLiteral[T]{Base: 2, Level: 1}
+
+	blindRotateParameters := make([]tfhe.GadgetParametersLiteral[T], 0, len(space.BlindRotateLevels))
+	for _, level := range space.BlindRotateLevels {
+		best, bestVariance := tfhe.GadgetParametersLiteral[T]{}, 0.0
+		for logBase := 1; logBase < logQ && logBase*level <= logQ; logBase++ {
+			params.BlindRotateParameters = tfhe.GadgetParametersLiteral[T]{Base: 1 << logBase, Level: level}
+			variance := params.Compile().NoiseBudget(target.Pipeline).BlindRotateVarianceSum()
+			if best.Level == 0 || variance < bestVariance {
+				best, bestVariance = params.BlindRotateParameters, variance
+			}
+		}
+		if best.Level != 0 {
+			blindRotateParameters = append(blindRotateParameters, best)
+		}
+	}
+
+	keySwitchParameters := make([]tfhe.GadgetParametersLiteral[T], 0, len(space.KeySwitchLevels))
+	for _, level := range space.KeySwitchLevels {
+		best, bestVariance := tfhe.GadgetParametersLiteral[T]{}, 0.0
+		for logBase := 1; logBase < logQ && logBase*level <= logQ; logBase++ {
+			params.KeySwitchParameters = tfhe.GadgetParametersLiteral[T]{Base: 1 << logBase, Level: level}
+			variance := params.Compile().NoiseBudget(target.Pipeline).KeySwitchVariance
+			if best.Level == 0 || variance < bestVariance {
+				best, bestVariance = params.KeySwitchParameters, variance
+			}
+		}
+		if best.Level != 0 {
+			keySwitchParameters = append(keySwitchParameters, best)
+		}
+	}
+
+	candidates := make([]Candidate[T], 0)
+	for _, blindRotateParams := range blindRotateParameters {
+		for _, keySwitchParams := range keySwitchParameters {
+			params.BlindRotateParameters = blindRotateParams
+			params.KeySwitchParameters = keySwitchParams
+
+			budget := params.Compile().NoiseBudget(target.Pipeline)
+			if budget.LogFailureProbability > target.LogFailureProbability {
+				continue
+			}
+
+			candidates = append(candidates, Candidate[T]{
+				Parameters:  params,
+				NoiseBudget: budget,
+				Cost:        EstimateCost(params.Compile(), target.Pipeline),
+			})
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,127 @@
+package paramsearch_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/sp301415/tfhe-go/tfhe/paramsearch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateStdDev(t *testing.T) {
+	params := tfhe.Params5.Compile()
+
+	t.Run("ParamsList", func(t *testing.T) {
+		assert.InEpsilon(t, params.LWEStdDev(), paramsearch.EstimateStdDev[uint64](params.LWEDimension(), 128), 0.01)
+		assert.InEpsilon(t, params.GLWEStdDev(), paramsearch.EstimateStdDev[uint64](params.GLWERank()*params.BasePolyDegree(), 128), 0.01)
+	})
+
+	t.Run("SecurityLevel", func(t *testing.T) {
+		// Higher security needs larger errors.
+		assert.Less(t, paramsearch.EstimateStdDev[uint64](1024, 80), paramsearch.EstimateStdDev[uint64](1024, 128))
+		assert.Less(t, paramsearch.EstimateStdDev[uint64](1024, 128), paramsearch.EstimateStdDev[uint64](1024, 192))
+	})
+
+	t.Run("MinStdDev", func(t *testing.T) {
+		assert.Equal(t, 3.2/math.Exp2(32), paramsearch.EstimateStdDev[uint32](4096, 128))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	// This space contains the shape of Params5 and ParamsEBS5.
+	space := paramsearch.Space{
+		LWEDimensions:     []int{640, 760, 880, 1000, 1164},
+		GLWERanks:         []int{1},
+		BasePolyDegrees:   []int{1024, 2048},
+		ExtendFactors:     []int{1, 2, 4},
+		BlindRotateLevels: []int{1, 2, 3},
+		KeySwitchLevels:   []int{2, 3, 4, 5, 6, 7},
+	}
+
+	for _, tc := range []struct {
+		pipeline tfhe.Pipeline
+		params   tfhe.ParametersLiteral[uint64]
+	}{
+		{tfhe.PipelineHierarchical, tfhe.Params5},
+		{tfhe.PipelineEBS, tfhe.ParamsEBS5},
+	} {
+		target := paramsearch.Target{
+			MessageBits:           5,
+			LogFailureProbability: -60,
+			SecurityLevel:         128,
+			Pipeline:              tc.pipeline,
+		}
+		candidates := paramsearch.Search[uint64](target, space)
+
+		t.Run(fmt.Sprintf("%v/Candidates", tc.pipeline), func(t *testing.T) {
+			assert.NotEmpty(t, candidates)
+			for i, c := range candidates {
+				params := c.Parameters.Compile()
+				assert.Equal(t, uint64(1<<target.MessageBits), params.MessageModulus())
+				assert.LessOrEqual(t, c.NoiseBudget.LogFailureProbability, target.LogFailureProbability)
+				assert.Equal(t, params.NoiseBudget(tc.pipeline), c.NoiseBudget)
+				assert.Equal(t, paramsearch.EstimateCost(params, tc.pipeline), c.Cost)
+				for depth := 0; depth <= params.HierarchyDepth(); depth++ {
+					security := params.AtDepth(depth).EstimateSecurity()
+					assert.GreaterOrEqual(t, security.LWE.Bits(), float64(target.SecurityLevel))
+					assert.GreaterOrEqual(t, security.GLWE.Bits(), float64(target.SecurityLevel))
+				}
+				if i > 0 {
+					assert.LessOrEqual(t, candidates[i-1].Cost, c.Cost)
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("%v/Pipeline", tc.pipeline), func(t *testing.T) {
+			for _, c := range candidates {
+				switch tc.pipeline {
+				case tfhe.PipelineHierarchical:
+					assert.Equal(t, tfhe.OrderBlindRotateKeySwitch, c.Parameters.BootstrapOrder)
+					assert.Equal(t, c.Parameters.PolyDegree, c.Parameters.LookUpTableSize)
+					assert.GreaterOrEqual(t, c.Parameters.Compile().HierarchyDepth(), 1)
+				case tfhe.PipelineEBS:
+					assert.Equal(t, tfhe.OrderKeySwitchBlindRotate, c.Parameters.BootstrapOrder)
+					assert.Equal(t, c.Parameters.BasePolyDegree, c.Parameters.PolyDegree)
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("%v/ParamsList", tc.pipeline), func(t *testing.T) {
+			params := tc.params.Compile()
+			assert.LessOrEqual(t, candidates[0].Cost, paramsearch.EstimateCost(params, tc.pipeline))
+		})
+	}
+
+	t.Run("Insecure", func(t *testing.T) {
+		// EstimateStdDev(640, 192) falls short of 192 bits, so LWEDimension 640 is dropped.
+		params := tfhe.ParamsEBS5.WithLWEDimension(640).WithLWEStdDev(paramsearch.EstimateStdDev[uint64](640, 192)).Compile()
+		assert.Less(t, params.EstimateSecurity().LWE.Bits(), 192.0)
+
+		target := paramsearch.Target{MessageBits: 5, LogFailureProbability: -60, SecurityLevel: 192, Pipeline: tfhe.PipelineEBS}
+		candidates := paramsearch.Search[uint64](target, space)
+		assert.NotEmpty(t, candidates)
+		for _, c := range candidates {
+			assert.NotEqual(t, 640, c.Parameters.LWEDimension)
+			assert.GreaterOrEqual(t, c.Parameters.Compile().EstimateSecurity().Bits(), 192.0)
+		}
+	})
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		target := paramsearch.Target{MessageBits: 5, LogFailureProbability: -1000, SecurityLevel: 128, Pipeline: tfhe.PipelineHierarchical}
+		assert.Empty(t, paramsearch.Search[uint64](target, space))
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			paramsearch.Search[uint64](paramsearch.Target{MessageBits: 0, SecurityLevel: 128}, space)
+		})
+		assert.Panics(t, func() {
+			paramsearch.Search[uint32](paramsearch.Target{MessageBits: 32, SecurityLevel: 128}, space)
+		})
+		assert.Panics(t, func() {
+			paramsearch.Search[uint64](paramsearch.Target{MessageBits: 5, SecurityLevel: 0}, space)
+		})
+	})
+}
@@ -0,0 +1,346 @@
+package tfhe
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/sp301415/tfhe-go/math/csprng"
+	"github.com/sp301415/tfhe-go/math/poly"
+	"github.com/sp301415/tfhe-go/math/vec"
+)
+
+// SeedSize is the size of the seed of seeded ciphertexts and keys in bytes.
+const SeedSize = 16
+
+// newSeed samples a new seed from crypto/rand.
+//
+// Panics when read from crypto/rand fails.
+func newSeed() []byte {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		panic(err)
+	}
+	return seed
+}
+
+// newSeededSampler returns a UniformSampler for the index-th entry of a seeded key.
+// Each entry uses the seed followed by its index,
+// so that entries can be sampled independently.
+func newSeededSampler[T TorusInt](seed []byte, index int) *csprng.UniformSampler[T] {
+	entrySeed := make([]byte, len(seed)+8)
+	copy(entrySeed, seed)
+	binary.BigEndian.PutUint64(entrySeed[len(seed):], uint64(index))
+	return csprng.NewUniformSamplerWithSeed[T](entrySeed)
+}
+
+// SeededLWECiphertext is a compressed LWE ciphertext,
+// where the mask is replaced by the seed of the UniformSampler that sampled it.
+// Use [SeededLWECiphertext.Expand] to recover the LWE ciphertext.
+type SeededLWECiphertext[T TorusInt] struct {
+	// Seed is the seed of the mask, of length SeedSize.
+	Seed []byte
+	// LWEDimension is the length of the mask.
+	LWEDimension int
+	// Value is the body.
+	Value T
+}
+
+// NewSeededLWECiphertext creates a new SeededLWECiphertext.
+func NewSeededLWECiphertext[T TorusInt](params Parameters[T]) SeededLWECiphertext[T] {
+	return NewSeededLWECiphertextCustom[T](params.DefaultLWEDimension())
+}
+
+// NewSeededLWECiphertextCustom creates a new SeededLWECiphertext with given dimension.
+func NewSeededLWECiphertextCustom[T TorusInt](lweDimension int) SeededLWECiphertext[T] {
+	return SeededLWECiphertext[T]{Seed: make([]byte, SeedSize), LWEDimension: lweDimension}
+}
+
+// Copy returns a copy of the ciphertext.
+func (ct SeededLWECiphertext[T]) Copy() SeededLWECiphertext[T] {
+	return SeededLWECiphertext[T]{Seed: vec.Copy(ct.Seed), LWEDimension: ct.LWEDimension, Value: ct.Value}
+}
+
+// CopyFrom copies values from the ciphertext.
+func (ct *SeededLWECiphertext[T]) CopyFrom(ctIn SeededLWECiphertext[T]) {
+	vec.CopyAssign(ctIn.Seed, ct.Seed)
+	ct.LWEDimension = ctIn.LWEDimension
+	ct.Value = ctIn.Value
+}
+
+// Clear clears the ciphertext.
+func (ct *SeededLWECiphertext[T]) Clear() {
+	vec.Fill(ct.Seed, 0)
+	ct.Value = 0
+}
+
+// Expand returns the LWE ciphertext with the mask sampled from the seed.
+func (ct SeededLWECiphertext[T]) Expand() LWECiphertext[T] {
+	ctOut := NewLWECiphertextCustom[T](ct.LWEDimension)
+	ct.ExpandAssign(ctOut)
+	return ctOut
+}
+
+// ExpandAssign samples the mask from the seed and writes the LWE ciphertext to ctOut.
+func (ct SeededLWECiphertext[T]) ExpandAssign(ctOut LWECiphertext[T]) {
+	csprng.NewUniformSamplerWithSeed[T](ct.Seed).SampleVecAssign(ctOut.Value[1:])
+	ctOut.Value[0] = ct.Value
+}
+
+// SeededGLWECiphertext is a compressed GLWE ciphertext,
+// where the mask is replaced by the seed of the UniformSampler that sampled it.
+// Use [SeededGLWECiphertext.Expand] to recover the GLWE ciphertext.
+type SeededGLWECiphertext[T TorusInt] struct {
+	// Seed is the seed of the mask, of length SeedSize.
+	Seed []byte
+	// GLWERank is the number of polynomials in the mask.
+	GLWERank int
+	// Value is the body.
+	Value poly.Poly[T]
+}
+
+// NewSeededGLWECiphertext creates a new SeededGLWECiphertext.
+func NewSeededGLWECiphertext[T TorusInt](params Parameters[T]) SeededGLWECiphertext[T] {
+	return NewSeededGLWECiphertextCustom[T](params.glweRank, params.polyDegree)
+}
+
+// NewSeededGLWECiphertextCustom creates a new SeededGLWECiphertext with given dimension and polyDegree.
+func NewSeededGLWECiphertextCustom[T TorusInt](glweRank, polyDegree int) SeededGLWECiphertext[T] {
+	return SeededGLWECiphertext[T]{Seed: make([]byte, SeedSize), GLWERank: glweRank, Value: poly.NewPoly[T](polyDegree)}
+}
+
+// Copy returns a copy of the ciphertext.
+func (ct SeededGLWECiphertext[T]) Copy() SeededGLWECiphertext[T] {
+	return SeededGLWECiphertext[T]{Seed: vec.Copy(ct.Seed), GLWERank: ct.GLWERank, Value: ct.Value.Copy()}
+}
+
+// CopyFrom copies values from the ciphertext.
+func (ct *SeededGLWECiphertext[T]) CopyFrom(ctIn SeededGLWECiphertext[T]) {
+	vec.CopyAssign(ctIn.Seed, ct.Seed)
+	ct.GLWERank = ctIn.GLWERank
+	ct.Value.CopyFrom(ctIn.Value)
+}
+
+// Clear clears the ciphertext.
+func (ct *SeededGLWECiphertext[T]) Clear() {
+	vec.Fill(ct.Seed, 0)
+	ct.Value.Clear()
+}
+
+// Expand returns the GLWE ciphertext with the mask sampled from the seed.
+func (ct SeededGLWECiphertext[T]) Expand() GLWECiphertext[T] {
+	ctOut := NewGLWECiphertextCustom[T](ct.GLWERank, ct.Value.Degree())
+	ct.ExpandAssign(ctOut)
+	return ctOut
+}
+
+// ExpandAssign samples the mask from the seed and writes the GLWE ciphertext to ctOut.
+func (ct SeededGLWECiphertext[T]) ExpandAssign(ctOut GLWECiphertext[T]) {
+	s := csprng.NewUniformSamplerWithSeed[T](ct.Seed)
+	for i := 0; i < ct.GLWERank; i++ {
+		s.SamplePolyAssign(ctOut.Value[i+1])
+	}
+	ctOut.Value[0].CopyFrom(ct.Value)
+}
+
+// SeededLWEKeySwitchKey is a compressed LWEKeySwitchKey,
+// where the masks are replaced by a single seed.
+// Use [SeededLWEKeySwitchKey.Expand] to recover the key.
+type SeededLWEKeySwitchKey[T TorusInt] struct {
+	GadgetParameters GadgetParameters[T]
+
+	// Seed is the seed of the masks, of length SeedSize.
+	// Masks of Value[i] are sampled from the seed followed by i.
+	Seed []byte
+	// OutputLWEDimension is the length of the masks.
+	OutputLWEDimension int
+	// Value has length InputLWEDimension,
+	// and Value[i] has length Level.
+	// Each element is the body of the LWE ciphertext in the LevCiphertext.
+	Value [][]T
+}
+
+// NewSeededKeySwitchKeyForBootstrap creates a new SeededLWEKeySwitchKey for bootstrapping.
+func NewSeededKeySwitchKeyForBootstrap[T TorusInt](params Parameters[T]) SeededLWEKeySwitchKey[T] {
+	return NewSeededLWEKeySwitchKeyCustom(params.glweDimension-params.lweDimension, params.lweDimension, params.keySwitchParameters)
+}
+
+// NewSeededLWEKeySwitchKeyCustom creates a new SeededLWEKeySwitchKey with custom parameters.
+func NewSeededLWEKeySwitchKeyCustom[T TorusInt](inputDimension, outputDimension int, gadgetParams GadgetParameters[T]) SeededLWEKeySwitchKey[T] {
+	ksk := make([][]T, inputDimension)
+	for i := 0; i < inputDimension; i++ {
+		ksk[i] = make([]T, gadgetParams.level)
+	}
+	return SeededLWEKeySwitchKey[T]{
+		GadgetParameters:   gadgetParams,
+		Seed:               make([]byte, SeedSize),
+		OutputLWEDimension: outputDimension,
+		Value:              ksk,
+	}
+}
+
+// InputLWEDimension returns the input LWEDimension of this key.
+func (ksk SeededLWEKeySwitchKey[T]) InputLWEDimension() int {
+	return len(ksk.Value)
+}
+
+// Copy returns a copy of the key.
+func (ksk SeededLWEKeySwitchKey[T]) Copy() SeededLWEKeySwitchKey[T] {
+	kskCopy := make([][]T, len(ksk.Value))
+	for i := range ksk.Value {
+		kskCopy[i] = vec.Copy(ksk.Value[i])
+	}
+	return SeededLWEKeySwitchKey[T]{
+		GadgetParameters:   ksk.GadgetParameters,
+		Seed:               vec.Copy(ksk.Seed),
+		OutputLWEDimension: ksk.OutputLWEDimension,
+		Value:              kskCopy,
+	}
+}
+
+// CopyFrom copies values from key.
+func (ksk *SeededLWEKeySwitchKey[T]) CopyFrom(kskIn SeededLWEKeySwitchKey[T]) {
+	for i := range ksk.Value {
+		vec.CopyAssign(kskIn.Value[i], ksk.Value[i])
+	}
+	vec.CopyAssign(kskIn.Seed, ksk.Seed)
+	ksk.GadgetParameters = kskIn.GadgetParameters
+	ksk.OutputLWEDimension = kskIn.OutputLWEDimension
+}
+
+// Clear clears the key.
+func (ksk *SeededLWEKeySwitchKey[T]) Clear() {
+	for i := range ksk.Value {
+		vec.Fill(ksk.Value[i], 0)
+	}
+	vec.Fill(ksk.Seed, 0)
+}
+
+// Expand returns the LWEKeySwitchKey with the masks sampled from the seed.
+func (ksk SeededLWEKeySwitchKey[T]) Expand() LWEKeySwitchKey[T] {
+	kskOut := NewLWEKeySwitchKeyCustom(ksk.InputLWEDimension(), ksk.OutputLWEDimension, ksk.GadgetParameters)
+	for i := range ksk.Value {
+		s := newSeededSampler[T](ksk.Seed, i)
+		for j := range ksk.Value[i] {
+			s.SampleVecAssign(kskOut.Value[i].Value[j].Value[1:])
+			kskOut.Value[i].Value[j].Value[0] = ksk.Value[i][j]
+		}
+	}
+	return kskOut
+}
+
+// SeededBlindRotateKey is a compressed BlindRotateKey,
+// where the masks are replaced by a single seed.
+// Unlike BlindRotateKey, the bodies are stored in the standard form,
+// so that the masks can be added before the Fourier transform.
+// Use [SeededBlindRotateKey.Expand] to recover the key.
+type SeededBlindRotateKey[T TorusInt] struct {
+	GadgetParameters GadgetParameters[T]
+
+	// Seed is the seed of the masks, of length SeedSize.
+	// Masks of Value[i] are sampled from the seed followed by i.
+	Seed []byte
+	// GLWERank is the number of polynomials in each mask.
+	GLWERank int
+	// Value has length LWEDimension,
+	// Value[i] has length GLWERank + 1, and Value[i][j] has length Level.
+	// Each element is the body of the GLWE ciphertext in the GGSWCiphertext.
+	Value [][][]poly.Poly[T]
+}
+
+// NewSeededBlindRotateKey creates a new SeededBlindRotateKey.
+func NewSeededBlindRotateKey[T TorusInt](params Parameters[T]) SeededBlindRotateKey[T] {
+	return NewSeededBlindRotateKeyCustom(params.lweDimension, params.glweRank, params.polyDegree, params.blindRotateParameters)
+}
+
+// NewSeededBlindRotateKeyCustom creates a new SeededBlindRotateKey with custom parameters.
+func NewSeededBlindRotateKeyCustom[T TorusInt](lweDimension, glweRank, polyDegree int, gadgetParams GadgetParameters[T]) SeededBlindRotateKey[T] {
+	brk := make([][][]poly.Poly[T], lweDimension)
+	for i := 0; i < lweDimension; i++ {
+		brk[i] = make([][]poly.Poly[T], glweRank+1)
+		for j := 0; j < glweRank+1; j++ {
+			brk[i][j] = make([]poly.Poly[T], gadgetParams.level)
+			for k := 0; k < gadgetParams.level; k++ {
+				brk[i][j][k] = poly.NewPoly[T](polyDegree)
+			}
+		}
+	}
+	return SeededBlindRotateKey[T]{
+		GadgetParameters: gadgetParams,
+		Seed:             make([]byte, SeedSize),
+		GLWERank:         glweRank,
+		Value:            brk,
+	}
+}
+
+// Copy returns a copy of the key.
+func (brk SeededBlindRotateKey[T]) Copy() SeededBlindRotateKey[T] {
+	brkCopy := make([][][]poly.Poly[T], len(brk.Value))
+	for i := range brk.Value {
+		brkCopy[i] = make([][]poly.Poly[T], len(brk.Value[i]))
+		for j := range brk.Value[i] {
+			brkCopy[i][j] = make([]poly.Poly[T], len(brk.Value[i][j]))
+			for k := range brk.Value[i][j] {
+				brkCopy[i][j][k] = brk.Value[i][j][k].Copy()
+			}
+		}
+	}
+	return SeededBlindRotateKey[T]{
+		GadgetParameters: brk.GadgetParameters,
+		Seed:             vec.Copy(brk.Seed),
+		GLWERank:         brk.GLWERank,
+		Value:            brkCopy,
+	}
+}
+
+// CopyFrom copies values from key.
+func (brk *SeededBlindRotateKey[T]) CopyFrom(brkIn SeededBlindRotateKey[T]) {
+	for i := range brk.Value {
+		for j := range brk.Value[i] {
+			for k := range brk.Value[i][j] {
+				brk.Value[i][j][k].CopyFrom(brkIn.Value[i][j][k])
+			}
+		}
+	}
+	vec.CopyAssign(brkIn.Seed, brk.Seed)
+	brk.GadgetParameters = brkIn.GadgetParameters
+	brk.GLWERank = brkIn.GLWERank
+}
+
+// Clear clears the key.
+func (brk *SeededBlindRotateKey[T]) Clear() {
+	for i := range brk.Value {
+		for j := range brk.Value[i] {
+			for k := range brk.Value[i][j] {
+				brk.Value[i][j][k].Clear()
+			}
+		}
+	}
+	vec.Fill(brk.Seed, 0)
+}
+
+// Expand returns the BlindRotateKey with the masks sampled from the seed.
+//
+// This applies the Fourier transform to every GLWE ciphertext, which can take a long time.
+func (brk SeededBlindRotateKey[T]) Expand() BlindRotateKey[T] {
+	lweDimension := len(brk.Value)
+	polyDegree := brk.Value[0][0][0].Degree()
+
+	brkOut := NewBlindRotateKeyCustom(lweDimension, brk.GLWERank, polyDegree, brk.GadgetParameters)
+	transformer := NewGLWETransformer[T](polyDegree)
+	ctGLWE := NewGLWECiphertextCustom[T](brk.GLWERank, polyDegree)
+
+	for i := range brk.Value {
+		s := newSeededSampler[T](brk.Seed, i)
+		for j := range brk.Value[i] {
+			for k := range brk.Value[i][j] {
+				for l := 0; l < brk.GLWERank; l++ {
+					s.SamplePolyAssign(ctGLWE.Value[l+1])
+				}
+				ctGLWE.Value[0].CopyFrom(brk.Value[i][j][k])
+				transformer.ToFourierGLWECiphertextAssign(ctGLWE, brkOut.Value[i].Value[j].Value[k])
+			}
+		}
+	}
+
+	return brkOut
+}
@@ -0,0 +1,93 @@
+package tfhe
+
+import (
+	"github.com/sp301415/tfhe-go/math/csprng"
+	"github.com/sp301415/tfhe-go/math/vec"
+)
+
+// EncryptSeededLWE encodes and encrypts integer message to seeded LWE ciphertext.
+func (e *Encryptor[T]) EncryptSeededLWE(message int) SeededLWECiphertext[T] {
+	return e.EncryptSeededLWEPlaintext(e.EncodeLWE(message))
+}
+
+// EncryptSeededLWEPlaintext encrypts LWE plaintext to seeded LWE ciphertext.
+// A new seed is sampled for every encryption.
+func (e *Encryptor[T]) EncryptSeededLWEPlaintext(pt LWEPlaintext[T]) SeededLWECiphertext[T] {
+	ctOut := SeededLWECiphertext[T]{Seed: newSeed(), LWEDimension: e.Parameters.DefaultLWEDimension()}
+
+	ct := NewLWECiphertext(e.Parameters)
+	ct.Value[0] = pt.Value
+	e.encryptLWEBodyWithSampler(csprng.NewUniformSamplerWithSeed[T](ctOut.Seed), ct)
+	ctOut.Value = ct.Value[0]
+
+	return ctOut
+}
+
+// EncryptSeededGLWE encodes and encrypts integer messages to seeded GLWE ciphertext.
+func (e *Encryptor[T]) EncryptSeededGLWE(messages []int) SeededGLWECiphertext[T] {
+	e.EncodeGLWEAssign(messages, e.buffer.ptGLWE)
+	return e.EncryptSeededGLWEPlaintext(e.buffer.ptGLWE)
+}
+
+// EncryptSeededGLWEPlaintext encrypts GLWE plaintext to seeded GLWE ciphertext.
+// A new seed is sampled for every encryption.
+func (e *Encryptor[T]) EncryptSeededGLWEPlaintext(pt GLWEPlaintext[T]) SeededGLWECiphertext[T] {
+	ctOut := NewSeededGLWECiphertext(e.Parameters)
+	copy(ctOut.Seed, newSeed())
+
+	e.buffer.ctGLWE.Value[0].CopyFrom(pt.Value)
+	e.encryptGLWEBodyWithSampler(csprng.NewUniformSamplerWithSeed[T](ctOut.Seed), e.buffer.ctGLWE)
+	ctOut.Value.CopyFrom(e.buffer.ctGLWE.Value[0])
+
+	return ctOut
+}
+
+// GenSeededKeySwitchKeyForBootstrap samples a new seeded keyswitch key LWELargeKey -> LWEKey,
+// used for bootstrapping.
+// Use [SeededLWEKeySwitchKey.Expand] to get the key for Evaluator.
+func (e *Encryptor[T]) GenSeededKeySwitchKeyForBootstrap() SeededLWEKeySwitchKey[T] {
+	skIn := LWESecretKey[T]{Value: e.SecretKey.LWELargeKey.Value[e.Parameters.lweDimension:]}
+	ksk := NewSeededKeySwitchKeyForBootstrap(e.Parameters)
+	copy(ksk.Seed, newSeed())
+
+	mask := make([]T, e.Parameters.lweDimension)
+	for i := 0; i < ksk.InputLWEDimension(); i++ {
+		s := newSeededSampler[T](ksk.Seed, i)
+		for j := 0; j < e.Parameters.keySwitchParameters.level; j++ {
+			s.SampleVecAssign(mask)
+			ksk.Value[i][j] = skIn.Value[i] << e.Parameters.keySwitchParameters.LogBaseQ(j)
+			ksk.Value[i][j] += -vec.Dot(mask, e.SecretKey.LWEKey.Value)
+			ksk.Value[i][j] += e.GaussianSampler.Sample(e.Parameters.LWEStdDevQ())
+		}
+	}
+
+	return ksk
+}
+
+// GenSeededBlindRotateKey samples a new seeded bootstrapping key.
+// Use [SeededBlindRotateKey.Expand] to get the key for Evaluator.
+//
+// This can take a long time.
+func (e *Encryptor[T]) GenSeededBlindRotateKey() SeededBlindRotateKey[T] {
+	brk := NewSeededBlindRotateKey(e.Parameters)
+	copy(brk.Seed, newSeed())
+
+	for i := 0; i < e.Parameters.lweDimension; i++ {
+		s := newSeededSampler[T](brk.Seed, i)
+		for j := 0; j < e.Parameters.glweRank+1; j++ {
+			if j == 0 {
+				e.buffer.ptGGSW.Clear()
+				e.buffer.ptGGSW.Coeffs[0] = e.SecretKey.LWEKey.Value[i]
+			} else {
+				e.PolyEvaluator.ScalarMulPolyAssign(e.SecretKey.GLWEKey.Value[j-1], e.SecretKey.LWEKey.Value[i], e.buffer.ptGGSW)
+			}
+			for k := 0; k < e.Parameters.blindRotateParameters.level; k++ {
+				e.PolyEvaluator.ScalarMulPolyAssign(e.buffer.ptGGSW, e.Parameters.blindRotateParameters.BaseQ(k), e.buffer.ctGLWE.Value[0])
+				e.encryptGLWEBodyWithSampler(s, e.buffer.ctGLWE)
+				brk.Value[i][j][k].CopyFrom(e.buffer.ctGLWE.Value[0])
+			}
+		}
+	}
+
+	return brk
+}
@@ -0,0 +1,641 @@
+package tfhe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/sp301415/tfhe-go/math/num"
+)
+
+// seedWriteTo writes the seed.
+func seedWriteTo(seed []byte, w io.Writer) (n int64, err error) {
+	nWrite, err := w.Write(seed)
+	return int64(nWrite), err
+}
+
+// seedReadFrom reads the seed.
+func seedReadFrom(seed []byte, r io.Reader) (n int64, err error) {
+	nRead, err := io.ReadFull(r, seed)
+	return int64(nRead), err
+}
+
+// ByteSize returns the size of the ciphertext in bytes.
+func (ct SeededLWECiphertext[T]) ByteSize() int {
+	return 8 + SeedSize + num.ByteSizeT[T]()
+}
+
+// headerWriteTo writes the header.
+func (ct SeededLWECiphertext[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	lweDimension := ct.LWEDimension
+	binary.BigEndian.PutUint64(buf[:], uint64(lweDimension))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	if nWrite64, err = seedWriteTo(ct.Seed, w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (ct SeededLWECiphertext[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	return vecWriteTo([]T{ct.Value}, w)
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] LWEDimension
+//	[16] Seed
+//	    Value
+func (ct SeededLWECiphertext[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = ct.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = ct.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(ct.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+func (ct *SeededLWECiphertext[T]) headerReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	lweDimension := int(binary.BigEndian.Uint64(buf[:]))
+
+	*ct = NewSeededLWECiphertextCustom[T](lweDimension)
+
+	if nRead64, err = seedReadFrom(ct.Seed, r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (ct *SeededLWECiphertext[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	var v [1]T
+	n, err = vecReadFrom(v[:], r)
+	ct.Value = v[0]
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (ct *SeededLWECiphertext[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = ct.headerReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = ct.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (ct SeededLWECiphertext[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, ct.ByteSize()))
+	_, err = ct.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (ct *SeededLWECiphertext[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := ct.ReadFrom(buf)
+	return err
+}
+
+// ByteSize returns the size of the ciphertext in bytes.
+func (ct SeededGLWECiphertext[T]) ByteSize() int {
+	return 16 + SeedSize + ct.Value.Degree()*num.ByteSizeT[T]()
+}
+
+// headerWriteTo writes the header.
+func (ct SeededGLWECiphertext[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	glweRank := ct.GLWERank
+	binary.BigEndian.PutUint64(buf[:], uint64(glweRank))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	polyDegree := ct.Value.Degree()
+	binary.BigEndian.PutUint64(buf[:], uint64(polyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	if nWrite64, err = seedWriteTo(ct.Seed, w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (ct SeededGLWECiphertext[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	return vecWriteTo(ct.Value.Coeffs, w)
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] GLWERank
+//	[8] PolyDegree
+//	[16] Seed
+//	    Value
+func (ct SeededGLWECiphertext[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = ct.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = ct.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(ct.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+func (ct *SeededGLWECiphertext[T]) headerReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	glweRank := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	polyDegree := int(binary.BigEndian.Uint64(buf[:]))
+
+	*ct = NewSeededGLWECiphertextCustom[T](glweRank, polyDegree)
+
+	if nRead64, err = seedReadFrom(ct.Seed, r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (ct *SeededGLWECiphertext[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	return vecReadFrom(ct.Value.Coeffs, r)
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (ct *SeededGLWECiphertext[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = ct.headerReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = ct.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (ct SeededGLWECiphertext[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, ct.ByteSize()))
+	_, err = ct.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (ct *SeededGLWECiphertext[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := ct.ReadFrom(buf)
+	return err
+}
+
+// ByteSize returns the size of the key in bytes.
+func (ksk SeededLWEKeySwitchKey[T]) ByteSize() int {
+	return 32 + SeedSize + len(ksk.Value)*ksk.GadgetParameters.level*num.ByteSizeT[T]()
+}
+
+// headerWriteTo writes the header.
+func (ksk SeededLWEKeySwitchKey[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	base := ksk.GadgetParameters.base
+	binary.BigEndian.PutUint64(buf[:], uint64(base))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	level := ksk.GadgetParameters.level
+	binary.BigEndian.PutUint64(buf[:], uint64(level))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	inputDimension := len(ksk.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(inputDimension))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	outputDimension := ksk.OutputLWEDimension
+	binary.BigEndian.PutUint64(buf[:], uint64(outputDimension))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	if nWrite64, err = seedWriteTo(ksk.Seed, w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (ksk SeededLWEKeySwitchKey[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	buf := make([]byte, ksk.GadgetParameters.level*num.ByteSizeT[T]())
+
+	for i := range ksk.Value {
+		if nWrite, err = vecWriteToBuffered(ksk.Value[i], buf, w); err != nil {
+			return n + nWrite, err
+		}
+		n += nWrite
+	}
+
+	return
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] Base
+//	[8] Level
+//	[8] InputDimension
+//	[8] OutputDimension
+//	[16] Seed
+//	    Value
+func (ksk SeededLWEKeySwitchKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = ksk.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = ksk.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(ksk.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+func (ksk *SeededLWEKeySwitchKey[T]) headerReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	base := T(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	level := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	inputDimension := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	outputDimension := int(binary.BigEndian.Uint64(buf[:]))
+
+	*ksk = NewSeededLWEKeySwitchKeyCustom(inputDimension, outputDimension, GadgetParametersLiteral[T]{Base: base, Level: level}.Compile())
+
+	if nRead64, err = seedReadFrom(ksk.Seed, r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (ksk *SeededLWEKeySwitchKey[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	buf := make([]byte, ksk.GadgetParameters.level*num.ByteSizeT[T]())
+
+	for i := range ksk.Value {
+		if nRead, err = vecReadFromBuffered(ksk.Value[i], buf, r); err != nil {
+			return n + nRead, err
+		}
+		n += nRead
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (ksk *SeededLWEKeySwitchKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = ksk.headerReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = ksk.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (ksk SeededLWEKeySwitchKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, ksk.ByteSize()))
+	_, err = ksk.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (ksk *SeededLWEKeySwitchKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := ksk.ReadFrom(buf)
+	return err
+}
+
+// ByteSize returns the size of the key in bytes.
+func (brk SeededBlindRotateKey[T]) ByteSize() int {
+	lweDimension := len(brk.Value)
+	polyDegree := brk.Value[0][0][0].Degree()
+
+	return 40 + SeedSize + lweDimension*(brk.GLWERank+1)*brk.GadgetParameters.level*polyDegree*num.ByteSizeT[T]()
+}
+
+// headerWriteTo writes the header.
+func (brk SeededBlindRotateKey[T]) headerWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int
+	var nWrite64 int64
+	var buf [8]byte
+
+	base := brk.GadgetParameters.base
+	binary.BigEndian.PutUint64(buf[:], uint64(base))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	level := brk.GadgetParameters.level
+	binary.BigEndian.PutUint64(buf[:], uint64(level))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	lweDimension := len(brk.Value)
+	binary.BigEndian.PutUint64(buf[:], uint64(lweDimension))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	glweRank := brk.GLWERank
+	binary.BigEndian.PutUint64(buf[:], uint64(glweRank))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+	polyDegree := brk.Value[0][0][0].Degree()
+	binary.BigEndian.PutUint64(buf[:], uint64(polyDegree))
+	if nWrite, err = w.Write(buf[:]); err != nil {
+		return n + int64(nWrite), err
+	}
+	n += int64(nWrite)
+
+	if nWrite64, err = seedWriteTo(brk.Seed, w); err != nil {
+		return n + nWrite64, err
+	}
+	n += nWrite64
+
+	return
+}
+
+// valueWriteTo writes the value.
+func (brk SeededBlindRotateKey[T]) valueWriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	polyDegree := brk.Value[0][0][0].Degree()
+	buf := make([]byte, polyDegree*num.ByteSizeT[T]())
+
+	for i := range brk.Value {
+		for j := range brk.Value[i] {
+			for k := range brk.Value[i][j] {
+				if nWrite, err = vecWriteToBuffered(brk.Value[i][j][k].Coeffs, buf, w); err != nil {
+					return n + nWrite, err
+				}
+				n += nWrite
+			}
+		}
+	}
+
+	return
+}
+
+// WriteTo implements the [io.WriterTo] interface.
+//
+// The encoded form is as follows:
+//
+//	[8] Base
+//	[8] Level
+//	[8] LWEDimension
+//	[8] GLWERank
+//	[8] PolyDegree
+//	[16] Seed
+//	    Value
+func (brk SeededBlindRotateKey[T]) WriteTo(w io.Writer) (n int64, err error) {
+	var nWrite int64
+
+	if nWrite, err = brk.headerWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if nWrite, err = brk.valueWriteTo(w); err != nil {
+		return n + nWrite, err
+	}
+	n += nWrite
+
+	if n < int64(brk.ByteSize()) {
+		return n, io.ErrShortWrite
+	}
+
+	return
+}
+
+// headerReadFrom reads the header, and initializes the value.
+func (brk *SeededBlindRotateKey[T]) headerReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int
+	var nRead64 int64
+	var buf [8]byte
+
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	base := T(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	level := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	lweDimension := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	glweRank := int(binary.BigEndian.Uint64(buf[:]))
+	if nRead, err = io.ReadFull(r, buf[:]); err != nil {
+		return n + int64(nRead), err
+	}
+	n += int64(nRead)
+	polyDegree := int(binary.BigEndian.Uint64(buf[:]))
+
+	*brk = NewSeededBlindRotateKeyCustom(lweDimension, glweRank, polyDegree, GadgetParametersLiteral[T]{Base: base, Level: level}.Compile())
+
+	if nRead64, err = seedReadFrom(brk.Seed, r); err != nil {
+		return n + nRead64, err
+	}
+	n += nRead64
+
+	return
+}
+
+// valueReadFrom reads the value.
+func (brk *SeededBlindRotateKey[T]) valueReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	polyDegree := brk.Value[0][0][0].Degree()
+	buf := make([]byte, polyDegree*num.ByteSizeT[T]())
+
+	for i := range brk.Value {
+		for j := range brk.Value[i] {
+			for k := range brk.Value[i][j] {
+				if nRead, err = vecReadFromBuffered(brk.Value[i][j][k].Coeffs, buf, r); err != nil {
+					return n + nRead, err
+				}
+				n += nRead
+			}
+		}
+	}
+
+	return
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface.
+func (brk *SeededBlindRotateKey[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	var nRead int64
+
+	if nRead, err = brk.headerReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	if nRead, err = brk.valueReadFrom(r); err != nil {
+		return n + nRead, err
+	}
+	n += nRead
+
+	return
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+func (brk SeededBlindRotateKey[T]) MarshalBinary() (data []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, brk.ByteSize()))
+	_, err = brk.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (brk *SeededBlindRotateKey[T]) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	_, err := brk.ReadFrom(buf)
+	return err
+}
@@ -0,0 +1,114 @@
+package tfhe_test
+
+import (
+	"testing"
+
+	"github.com/sp301415/tfhe-go/math/vec"
+	"github.com/sp301415/tfhe-go/tfhe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeeded(t *testing.T) {
+	t.Run("LWE", func(t *testing.T) {
+		ct := enc.EncryptSeededLWE(3)
+		assert.Equal(t, 3, enc.DecryptLWE(ct.Expand()))
+		assert.Equal(t, ct.Expand(), ct.Expand())
+
+		data, err := ct.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, ct.ByteSize(), len(data))
+
+		var ctOut tfhe.SeededLWECiphertext[uint64]
+		assert.NoError(t, ctOut.UnmarshalBinary(data))
+		assert.Equal(t, ct, ctOut)
+
+		t.Logf("Seeded=%vB Full=%vB", ct.ByteSize(), ct.Expand().ByteSize())
+		assert.Less(t, ct.ByteSize(), ct.Expand().ByteSize())
+	})
+
+	t.Run("GLWE", func(t *testing.T) {
+		messages := []int{1, 2, 3, 4, 5}
+		ct := enc.EncryptSeededGLWE(messages)
+		assert.Equal(t, messages, enc.DecryptGLWE(ct.Expand())[:len(messages)])
+		assert.Equal(t, ct.Expand(), ct.Expand())
+
+		data, err := ct.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, ct.ByteSize(), len(data))
+
+		var ctOut tfhe.SeededGLWECiphertext[uint64]
+		assert.NoError(t, ctOut.UnmarshalBinary(data))
+		assert.Equal(t, ct, ctOut)
+
+		t.Logf("Seeded=%vB Full=%vB", ct.ByteSize(), ct.Expand().ByteSize())
+		assert.Less(t, ct.ByteSize(), ct.Expand().ByteSize())
+	})
+
+	t.Run("EvaluationKey", func(t *testing.T) {
+		brk := enc.GenSeededBlindRotateKey()
+		ksk := enc.GenSeededKeySwitchKeyForBootstrap()
+
+		evk := tfhe.EvaluationKey[uint64]{
+			BlindRotateKey: brk.Expand(),
+			KeySwitchKey:   ksk.Expand(),
+		}
+		evalSeeded := tfhe.NewEvaluator(params, evk)
+
+		f := func(x int) int { return 2*x + 5 }
+		decomposedLUT := evalSeeded.NewDecomposedLutEBS()
+		evalSeeded.GenLookUpTableNegDecomposedEBSAssign(f, params.MessageModulus(), params.Scale(), &decomposedLUT)
+		compressLUT := tfhe.NewLookUpTable(params)
+		evalSeeded.GenCompressLUTAssign(compressLUT)
+
+		messageModulus := int(params.MessageModulus())
+		for _, x := range []int{0, messageModulus/2 + 1, messageModulus - 1} {
+			ct := enc.EncryptLWE(x)
+			evalSeeded.BootstrapExtendedFullDomainAssignNew(ct, compressLUT, decomposedLUT, ct)
+			assert.Equal(t, f(x)%messageModulus, enc.DecryptLWE(ct))
+		}
+
+		// Every entry of the key switching key is an encryption of the LWELargeKey.
+		for _, i := range []int{0, ksk.InputLWEDimension() - 1} {
+			lweLargeKey := enc.SecretKey.LWELargeKey.Value[params.LWEDimension()+i]
+			assert.Equal(t, int(lweLargeKey), int(enc.DecryptLevScalar(evk.KeySwitchKey.Value[i])))
+		}
+
+		t.Logf("BlindRotateKey: Seeded=%vB Full=%vB", brk.ByteSize(), evk.BlindRotateKey.ByteSize())
+		t.Logf("KeySwitchKey: Seeded=%vB Full=%vB", ksk.ByteSize(), evk.KeySwitchKey.ByteSize())
+		assert.Less(t, brk.ByteSize(), evk.BlindRotateKey.ByteSize())
+		assert.Less(t, ksk.ByteSize(), evk.KeySwitchKey.ByteSize())
+		// Only the bodies remain, so the key switching key shrinks by a factor of about LWEDimension + 1.
+		assert.Less(t, ksk.ByteSize()*(params.LWEDimension()/2), evk.KeySwitchKey.ByteSize())
+		// With GLWERank 1, half of each GLWE ciphertext is the mask.
+		assert.InEpsilon(t, 2.0, float64(evk.BlindRotateKey.ByteSize())/float64(brk.ByteSize()), 1e-3)
+
+		t.Run("Marshal", func(t *testing.T) {
+			brkData, err := brk.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, brk.ByteSize(), len(brkData))
+
+			var brkOut tfhe.SeededBlindRotateKey[uint64]
+			assert.NoError(t, brkOut.UnmarshalBinary(brkData))
+			assert.Equal(t, brk, brkOut)
+
+			kskData, err := ksk.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, ksk.ByteSize(), len(kskData))
+
+			var kskOut tfhe.SeededLWEKeySwitchKey[uint64]
+			assert.NoError(t, kskOut.UnmarshalBinary(kskData))
+			assert.Equal(t, ksk, kskOut)
+			assert.Equal(t, evk.KeySwitchKey, kskOut.Expand())
+		})
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		ct := enc.EncryptSeededLWE(3)
+		ctCopy := ct.Copy()
+		assert.Equal(t, ct, ctCopy)
+
+		ctCopy.Clear()
+		assert.Equal(t, make([]byte, tfhe.SeedSize), ctCopy.Seed)
+		assert.False(t, vec.Equals(ct.Seed, ctCopy.Seed))
+	})
+}